@@ -0,0 +1,105 @@
+// Package config merges the settings of the components from several sources.
+// Settings are grouped in sections, e.g. "server" or "log.hook1", each section being the key/value
+// form of a setting string. Sources loaded later override the keys of the previous ones.
+package config
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/jedrp/go-core/util"
+)
+
+// Section names of the components of this module
+const (
+	ServerSection = "server"
+	LogSection    = "log"
+)
+
+// Section holds the settings of one component
+type Section map[string]string
+
+// String formats the section as a setting string
+func (s Section) String() string {
+	return util.FormatConfig(s)
+}
+
+// Redacted formats the section as a setting string whose sensitive values are redacted, e.g. to be logged
+func (s Section) Redacted() string {
+	return util.FormatConfig(util.Redact(s))
+}
+
+// Config is the merged settings of all the sources
+type Config struct {
+	sections map[string]Section
+}
+
+// Source provides settings by section name
+type Source interface {
+	Load() (map[string]Section, error)
+}
+
+// SourceFunc adapts a function to a Source
+type SourceFunc func() (map[string]Section, error)
+
+func (f SourceFunc) Load() (map[string]Section, error) {
+	return f()
+}
+
+// Load merges the sources by key, a source overrides the keys of the sources before it.
+// Secret references of the merged settings are then resolved, see util.ResolveSecrets.
+func Load(sources ...Source) (*Config, error) {
+	c := &Config{sections: make(map[string]Section)}
+	for _, source := range sources {
+		sections, err := source.Load()
+		if err != nil {
+			return nil, err
+		}
+		c.merge(sections)
+	}
+	for name, section := range c.sections {
+		if err := util.ResolveSecrets(section); err != nil {
+			return nil, fmt.Errorf("section %s: %w", name, err)
+		}
+	}
+	return c, nil
+}
+
+// Default returns the current configuration of the process, loaded from, by increasing precedence,
+// the file given by --config-file or CONFIG_FILE, the environment variables and the command line flags.
+// It changes when Watch() reloads it. The process exits when the configuration cannot be loaded.
+func Default() *Config {
+	return Watch().Current()
+}
+
+// Section returns a copy of the settings of the section, empty when the section is not configured
+func (c *Config) Section(name string) Section {
+	section := make(Section, len(c.sections[name]))
+	for k, v := range c.sections[name] {
+		section[k] = v
+	}
+	return section
+}
+
+// Sections returns the sorted names of the configured sections
+func (c *Config) Sections() []string {
+	names := make([]string, 0, len(c.sections))
+	for name := range c.sections {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func (c *Config) merge(sections map[string]Section) {
+	for name, section := range sections {
+		target, found := c.sections[name]
+		if !found {
+			target = make(Section, len(section))
+			c.sections[name] = target
+		}
+		for k, v := range section {
+			target[k] = v
+		}
+	}
+}
@@ -0,0 +1,114 @@
+package config
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"reflect"
+	"testing"
+
+	"github.com/jedrp/go-core/util"
+)
+
+func TestLoadPrecedence(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "config.yaml")
+	err := ioutil.WriteFile(file, []byte(`
+server: "host=0.0.0.0;port=80;readTimeout=10"
+log:
+  level: info
+  hook1:
+    type: es
+    host: [http://es1:9200, http://es2:9200]
+    sniff: true
+`), 0600)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Setenv("SERVER_CONFIG", "port=8080;writeTimeout=20")
+
+	c, err := Load(
+		Defaults(map[string]Section{ServerSection: {"port": "0", "keepAlive": "0"}}),
+		File(file),
+		Env(),
+		Flags([]string{"--server-config=port=9090", "--unknown=value"}),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	expectedServer := Section{
+		"host":         "0.0.0.0",
+		"port":         "9090",
+		"keepAlive":    "0",
+		"readTimeout":  "10",
+		"writeTimeout": "20",
+	}
+	if s := c.Section(ServerSection); !reflect.DeepEqual(s, expectedServer) {
+		t.Errorf("expected %v but got %v", expectedServer, s)
+	}
+	expectedHook := Section{"type": "es", "host": "http://es1:9200,http://es2:9200", "sniff": "true"}
+	if s := c.Section("log.hook1"); !reflect.DeepEqual(s, expectedHook) {
+		t.Errorf("expected %v but got %v", expectedHook, s)
+	}
+	if s := c.Section(LogSection); !reflect.DeepEqual(s, Section{"level": "info"}) {
+		t.Errorf("unexpected log section %v", s)
+	}
+	if names := c.Sections(); !reflect.DeepEqual(names, []string{"log", "log.hook1", "server"}) {
+		t.Errorf("unexpected sections %v", names)
+	}
+	if s := c.Section("missing"); s == nil || len(s) != 0 {
+		t.Errorf("expected empty section but got %v", s)
+	}
+}
+
+func TestJSONFile(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.json")
+	if err := ioutil.WriteFile(file, []byte(`{"server": {"port": 80, "host": "localhost"}}`), 0600); err != nil {
+		t.Fatal(err)
+	}
+	c, err := Load(File(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if s := c.Section(ServerSection); s.String() != "host=localhost;port=80" {
+		t.Errorf("unexpected server section %s", s)
+	}
+}
+
+func TestFileNumbers(t *testing.T) {
+	dir := t.TempDir()
+	for name, content := range map[string]string{
+		"config.json": `{"rest": {"maxBody": 10485760, "ratio": 0.5}}`,
+		"config.yaml": "rest:\n  maxBody: 1.048576e+7\n  ratio: 0.5\n",
+	} {
+		file := filepath.Join(dir, name)
+		if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+		c, err := Load(File(file))
+		if err != nil {
+			t.Fatal(err)
+		}
+		var settings struct {
+			MaxBody int     `setting:"maxBody"`
+			Ratio   float64 `setting:"ratio"`
+		}
+		if err := util.Bind(c.Section("rest"), &settings); err != nil {
+			t.Errorf("%s: %v", name, err)
+		}
+		if settings.MaxBody != 10485760 || settings.Ratio != 0.5 {
+			t.Errorf("%s: unexpected settings %+v", name, settings)
+		}
+	}
+}
+
+func TestLoadErrors(t *testing.T) {
+	if _, err := Load(File(filepath.Join(os.TempDir(), "missing.yaml"))); err == nil {
+		t.Error("expected error on missing file")
+	}
+	t.Setenv("LOG_CONFIG", `level="debug`)
+	if _, err := Load(Env()); err == nil {
+		t.Error("expected error on invalid setting string")
+	}
+}
@@ -0,0 +1,169 @@
+package config
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+
+	"github.com/jedrp/go-core/util"
+)
+
+var (
+	schemasMux sync.RWMutex
+	schemas    = make(map[string][]util.SettingSpec)
+)
+
+// RegisterSchema declares the settings of a section, usually from util.SchemaOf in the init of a component.
+// Components sharing a section merge their settings, the first declaration of a key wins.
+func RegisterSchema(section string, schema []util.SettingSpec) {
+	schemasMux.Lock()
+	defer schemasMux.Unlock()
+	for _, spec := range schema {
+		found := false
+		for _, s := range schemas[section] {
+			if s.Key == spec.Key {
+				found = true
+				break
+			}
+		}
+		if !found {
+			schemas[section] = append(schemas[section], spec)
+		}
+	}
+}
+
+// Schema returns the settings declared for the section
+func Schema(section string) []util.SettingSpec {
+	schemasMux.RLock()
+	defer schemasMux.RUnlock()
+	return append([]util.SettingSpec(nil), schemas[section]...)
+}
+
+// UnknownKeys returns the keys of the section not declared by its schema, nil when no schema is registered
+func (c *Config) UnknownKeys(section string) []string {
+	schema := Schema(section)
+	if len(schema) == 0 {
+		return nil
+	}
+	return util.UnknownKeys(c.sections[section], schema)
+}
+
+// WriteDump writes the sections of c with their sensitive values redacted, including the defaults
+// of the declared settings which are not configured. Sections not configured are written only when
+// they have no required setting, e.g. the optional log hooks are omitted.
+func WriteDump(w io.Writer, c *Config) error {
+	names := c.Sections()
+	schemasMux.RLock()
+	for name, schema := range schemas {
+		if _, found := c.sections[name]; !found && !hasRequired(schema) {
+			names = append(names, name)
+		}
+	}
+	schemasMux.RUnlock()
+	sort.Strings(names)
+
+	for i, name := range names {
+		if i > 0 {
+			fmt.Fprintln(w)
+		}
+		fmt.Fprintf(w, "[%s]\n", name)
+		lines := make(map[string]string)
+		for k, v := range util.Redact(c.Section(name)) {
+			lines[k] = v
+		}
+		for _, spec := range Schema(name) {
+			if _, found := lines[spec.Key]; !found && spec.Default != "" {
+				lines[spec.Key] = spec.Default + " (default)"
+			}
+		}
+		keys := make([]string, 0, len(lines))
+		for k := range lines {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			fmt.Fprintf(w, "%s = %s\n", k, lines[k])
+		}
+		for _, k := range c.UnknownKeys(name) {
+			fmt.Fprintf(w, "# %s is not a known setting\n", k)
+		}
+	}
+	return nil
+}
+
+func hasRequired(schema []util.SettingSpec) bool {
+	for _, spec := range schema {
+		if spec.Required {
+			return true
+		}
+	}
+	return false
+}
+
+// WriteReference writes the table of the declared settings of all the sections
+func WriteReference(w io.Writer) error {
+	schemasMux.RLock()
+	names := make([]string, 0, len(schemas))
+	for name := range schemas {
+		names = append(names, name)
+	}
+	schemasMux.RUnlock()
+	sort.Strings(names)
+
+	tw := tabwriter.NewWriter(w, 0, 4, 2, ' ', 0)
+	fmt.Fprintln(tw, "SECTION\tKEY\tTYPE\tDEFAULT\tDESCRIPTION")
+	for _, name := range names {
+		for _, spec := range Schema(name) {
+			description := spec.Description
+			if spec.Required {
+				description = "required, " + description
+			}
+			if len(spec.Enum) > 0 {
+				description += " (one of " + strings.Join(spec.Enum, ", ") + ")"
+			}
+			if spec.Unit != "" {
+				description += " (bare numbers in " + spec.Unit + ")"
+			}
+			fmt.Fprintf(tw, "%s\t%s\t%s\t%s\t%s\n", name, spec.Key, spec.Type, spec.Default, description)
+		}
+	}
+	return tw.Flush()
+}
+
+// HandleCommands prints the redacted effective configuration when --config-dump is given,
+// the reference of the settings when --config-reference is given, then exits.
+// Call it first in main, the schemas of the imported packages being registered by then.
+// While a command is requested the default loggers write to stderr, see CommandRequested.
+func HandleCommands() {
+	opts, err := parseFlags(os.Args[1:])
+	if err != nil {
+		exit(err)
+	}
+	switch {
+	case opts.Dump:
+		err = WriteDump(os.Stdout, Default())
+	case opts.Reference:
+		err = WriteReference(os.Stdout)
+	default:
+		return
+	}
+	if err != nil {
+		exit(err)
+	}
+	os.Exit(0)
+}
+
+// CommandRequested reports whether the command line asks HandleCommands to print the configuration,
+// stdout is then reserved for its output
+func CommandRequested() bool {
+	return commandRequested(os.Args[1:])
+}
+
+func commandRequested(args []string) bool {
+	opts, err := parseFlags(args)
+	return err == nil && (opts.Dump || opts.Reference)
+}
@@ -0,0 +1,61 @@
+package config
+
+import (
+	"bytes"
+	"strings"
+	"testing"
+
+	"github.com/jedrp/go-core/util"
+)
+
+func TestDumpAndReference(t *testing.T) {
+	type settings struct {
+		Host     string `setting:"host" required:"true" description:"the host"`
+		Port     int    `setting:"port" default:"80" description:"the port"`
+		Password string `setting:"password" description:"the password"`
+	}
+	RegisterSchema("test.schema", util.SchemaOf(settings{}))
+
+	c, err := Load(Defaults(map[string]Section{"test.schema": {"host": "localhost", "password": "secret", "prot": "81"}}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if unknown := c.UnknownKeys("test.schema"); len(unknown) != 1 || unknown[0] != "prot" {
+		t.Errorf("expected prot to be unknown but got %v", unknown)
+	}
+
+	var b bytes.Buffer
+	if err := WriteDump(&b, c); err != nil {
+		t.Fatal(err)
+	}
+	dump := b.String()
+	expected := "[test.schema]\nhost = localhost\npassword = *****\nport = 80 (default)\nprot = 81\n# prot is not a known setting\n"
+	if !strings.Contains(dump, expected) {
+		t.Errorf("expected dump to contain\n%s\nbut got\n%s", expected, dump)
+	}
+
+	b.Reset()
+	if err := WriteReference(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), "test.schema  host") || !strings.Contains(b.String(), "required, the host") {
+		t.Errorf("unexpected reference\n%s", b.String())
+	}
+}
+
+func TestCommandRequested(t *testing.T) {
+	tt := []struct {
+		args     []string
+		expected bool
+	}{
+		{args: nil},
+		{args: []string{"--config-file", "config.yaml", "--unknown"}},
+		{args: []string{"--config-dump"}, expected: true},
+		{args: []string{"--log-config", "level=info", "--config-reference"}, expected: true},
+	}
+	for _, tc := range tt {
+		if requested := commandRequested(tc.args); requested != tc.expected {
+			t.Errorf("%v: expected %v but got %v", tc.args, tc.expected, requested)
+		}
+	}
+}
@@ -0,0 +1,219 @@
+package config
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/jedrp/go-core/util"
+	flags "github.com/jessevdk/go-flags"
+	"gopkg.in/yaml.v2"
+)
+
+// EnvVars maps the environment variables holding a setting string to their section
+var EnvVars = map[string]string{
+	"SERVER_CONFIG": ServerSection,
+	"LOG_CONFIG":    LogSection,
+	"LOG_HOOK_1":    LogSection + ".hook1",
+	"LOG_HOOK_2":    LogSection + ".hook2",
+	"LOG_HOOK_3":    LogSection + ".hook3",
+	"LOG_HOOK_4":    LogSection + ".hook4",
+}
+
+// Defaults provides fixed settings, usually the first source
+func Defaults(sections map[string]Section) Source {
+	return SourceFunc(func() (map[string]Section, error) {
+		return sections, nil
+	})
+}
+
+// Env provides the setting strings of the environment variables listed in EnvVars
+func Env() Source {
+	return SourceFunc(func() (map[string]Section, error) {
+		sections := make(map[string]Section)
+		for name, section := range EnvVars {
+			if err := addSettingString(sections, section, os.Getenv(name)); err != nil {
+				return nil, fmt.Errorf("%s: %w", name, err)
+			}
+		}
+		return sections, nil
+	})
+}
+
+// File provides the settings of a YAML or JSON file, JSON being used for the .json extension.
+// Top level keys are sections whose value is either a setting string or a map of settings,
+// nested maps are sections named "parent.child" and lists are comma separated:
+//
+//	server: "host=0.0.0.0;port=80"
+//	log:
+//	  level: info
+//	  hook1:
+//	    type: es
+//	    host: [http://es1:9200, http://es2:9200]
+func File(path string) Source {
+	return fileSource(path)
+}
+
+// fileSource is polled by the Watcher
+type fileSource string
+
+func (f fileSource) Load() (map[string]Section, error) {
+	path := string(f)
+	b, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	var content map[string]interface{}
+	if strings.EqualFold(filepath.Ext(path), ".json") {
+		// numbers are kept as written, float64 would print large integers in scientific notation
+		decoder := json.NewDecoder(bytes.NewReader(b))
+		decoder.UseNumber()
+		err = decoder.Decode(&content)
+	} else {
+		err = yaml.Unmarshal(b, &content)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	sections := make(map[string]Section)
+	for name, v := range content {
+		if err := addFileSection(sections, name, v); err != nil {
+			return nil, fmt.Errorf("%s: %w", path, err)
+		}
+	}
+	return sections, nil
+}
+
+// Flags provides the setting strings of the command line flags, unknown flags are ignored
+func Flags(args []string) Source {
+	return SourceFunc(func() (map[string]Section, error) {
+		opts, err := parseFlags(args)
+		if err != nil {
+			return nil, err
+		}
+		return opts.Load()
+	})
+}
+
+// options command line flags read by Default
+type options struct {
+	ConfigFile string `long:"config-file" description:"the YAML or JSON configuration file" env:"CONFIG_FILE"`
+	Server     string `long:"server-config" description:"the server setting string"`
+	Log        string `long:"log-config" description:"the log setting string"`
+	LogHook1   string `long:"log-hook-1" description:"the hook connection string"`
+	LogHook2   string `long:"log-hook-2" description:"the hook connection string"`
+	LogHook3   string `long:"log-hook-3" description:"the hook connection string"`
+	LogHook4   string `long:"log-hook-4" description:"the hook connection string"`
+	Dump       bool   `long:"config-dump" description:"print the effective configuration with redacted secrets and exit"`
+	Reference  bool   `long:"config-reference" description:"print the reference of the settings and exit"`
+}
+
+func parseFlags(args []string) (*options, error) {
+	opts := &options{}
+	parser := flags.NewParser(opts, flags.IgnoreUnknown)
+	if _, err := parser.ParseArgs(args); err != nil {
+		return nil, err
+	}
+	return opts, nil
+}
+
+func (o *options) Load() (map[string]Section, error) {
+	sections := make(map[string]Section)
+	for section, str := range map[string]string{
+		ServerSection:         o.Server,
+		LogSection:            o.Log,
+		LogSection + ".hook1": o.LogHook1,
+		LogSection + ".hook2": o.LogHook2,
+		LogSection + ".hook3": o.LogHook3,
+		LogSection + ".hook4": o.LogHook4,
+	} {
+		if err := addSettingString(sections, section, str); err != nil {
+			return nil, fmt.Errorf("%s flag: %w", section, err)
+		}
+	}
+	return sections, nil
+}
+
+func addSettingString(sections map[string]Section, name, str string) error {
+	config, err := util.GetConfig(str)
+	if err != nil || config == nil {
+		return err
+	}
+	sections[name] = config
+	return nil
+}
+
+func addFileSection(sections map[string]Section, name string, v interface{}) error {
+	switch value := v.(type) {
+	case nil:
+		return nil
+	case string:
+		return addSettingString(sections, name, value)
+	case map[string]interface{}, map[interface{}]interface{}:
+		section := Section{}
+		sections[name] = section
+		entries := mapEntries(value)
+		keys := make([]string, 0, len(entries))
+		for k := range entries {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			switch entry := entries[k].(type) {
+			case map[string]interface{}, map[interface{}]interface{}:
+				if err := addFileSection(sections, name+"."+k, entry); err != nil {
+					return err
+				}
+			default:
+				section[k] = scalar(entry)
+			}
+		}
+		return nil
+	default:
+		return fmt.Errorf("section %s must be a setting string or a map", name)
+	}
+}
+
+func mapEntries(v interface{}) map[string]interface{} {
+	if m, ok := v.(map[string]interface{}); ok {
+		return m
+	}
+	entries := make(map[string]interface{})
+	for k, entry := range v.(map[interface{}]interface{}) {
+		entries[fmt.Sprint(k)] = entry
+	}
+	return entries
+}
+
+func scalar(v interface{}) string {
+	switch value := v.(type) {
+	case nil:
+		return ""
+	case []interface{}:
+		items := make([]string, 0, len(value))
+		for _, item := range value {
+			items = append(items, scalar(item))
+		}
+		return strings.Join(items, ",")
+	case float64:
+		// e.g. YAML floats, fmt.Sprint would write 1e+07
+		return strconv.FormatFloat(value, 'f', -1, 64)
+	default:
+		return fmt.Sprint(value)
+	}
+}
+
+func exit(err error) {
+	code := 1
+	if fe, ok := err.(*flags.Error); ok && fe.Type == flags.ErrHelp {
+		code = 0
+	}
+	fmt.Fprintln(os.Stderr, err)
+	os.Exit(code)
+}
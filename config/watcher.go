@@ -0,0 +1,211 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"os/signal"
+	"reflect"
+	"sync"
+	"syscall"
+	"time"
+
+	"github.com/jedrp/go-core/util"
+)
+
+// Watcher reloads the configuration from its sources when a file changes or on SIGHUP.
+// A new snapshot is applied only when all the validators accept it, subscribers are then notified.
+type Watcher struct {
+	sources []Source
+	// serializes the reloads, the validators run without holding mux so Current is not blocked by them
+	reloadMux sync.Mutex
+
+	mux         sync.RWMutex
+	current     *Config
+	validators  []func(*Config) error
+	subscribers []func(old, new *Config)
+	onError     func(error)
+
+	stopOnce sync.Once
+	stop     chan struct{}
+}
+
+var (
+	defaultWatcher     *Watcher
+	defaultWatcherOnce sync.Once
+)
+
+// NewWatcher loads the sources, see Load
+func NewWatcher(sources ...Source) (*Watcher, error) {
+	c, err := Load(sources...)
+	if err != nil {
+		return nil, err
+	}
+	return &Watcher{
+		sources: sources,
+		current: c,
+		onError: func(err error) { fmt.Fprintf(os.Stderr, "configuration reload failed: %v\n", err) },
+		stop:    make(chan struct{}),
+	}, nil
+}
+
+// Watch returns the watcher of the default sources, see Default. It is not started.
+func Watch() *Watcher {
+	defaultWatcherOnce.Do(func() {
+		opts, err := parseFlags(os.Args[1:])
+		if err != nil {
+			exit(err)
+		}
+		sources := []Source{}
+		if opts.ConfigFile != "" {
+			sources = append(sources, File(opts.ConfigFile))
+		}
+		sources = append(sources, Env(), opts)
+		if defaultWatcher, err = NewWatcher(sources...); err != nil {
+			exit(err)
+		}
+	})
+	return defaultWatcher
+}
+
+// Current returns the last applied snapshot
+func (w *Watcher) Current() *Config {
+	w.mux.RLock()
+	defer w.mux.RUnlock()
+	return w.current
+}
+
+// Validate registers a check a new snapshot must pass to be applied
+func (w *Watcher) Validate(validator func(*Config) error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.validators = append(w.validators, validator)
+}
+
+// Subscribe registers a callback notified after a new snapshot is applied
+func (w *Watcher) Subscribe(subscriber func(old, new *Config)) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.subscribers = append(w.subscribers, subscriber)
+}
+
+// OnError sets the handler of the failures of the background reloads, they are printed to stderr by default
+func (w *Watcher) OnError(handler func(error)) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	w.onError = handler
+}
+
+// Reload loads and validates a new snapshot, the current one is kept when it fails.
+// Validators may call Current, which returns the previous snapshot until they all pass.
+func (w *Watcher) Reload() error {
+	w.reloadMux.Lock()
+	defer w.reloadMux.Unlock()
+	c, err := Load(w.sources...)
+	if err != nil {
+		return err
+	}
+	w.mux.RLock()
+	validators := w.validators
+	w.mux.RUnlock()
+	for _, validate := range validators {
+		if err := validate(c); err != nil {
+			return err
+		}
+	}
+	w.mux.Lock()
+	old := w.current
+	w.current = c
+	subscribers := w.subscribers
+	w.mux.Unlock()
+
+	for _, notify := range subscribers {
+		notify(old, c)
+	}
+	return nil
+}
+
+// Start reloads the configuration in background on SIGHUP and, when interval is positive,
+// when the modification time or the size of a file source changes
+func (w *Watcher) Start(interval time.Duration) {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+
+	var (
+		ticker *time.Ticker
+		ticks  <-chan time.Time
+	)
+	if interval > 0 {
+		ticker = time.NewTicker(interval)
+		ticks = ticker.C
+	}
+	stamps := w.fileStamps()
+	go func() {
+		defer signal.Stop(signals)
+		if ticker != nil {
+			defer ticker.Stop()
+		}
+		for {
+			select {
+			case <-w.stop:
+				return
+			case <-signals:
+			case <-ticks:
+				current := w.fileStamps()
+				if reflect.DeepEqual(current, stamps) {
+					continue
+				}
+				stamps = current
+			}
+			if err := w.Reload(); err != nil {
+				w.mux.RLock()
+				onError := w.onError
+				w.mux.RUnlock()
+				onError(err)
+			}
+		}
+	}()
+}
+
+// Stop stops the background reloads
+func (w *Watcher) Stop() {
+	w.stopOnce.Do(func() { close(w.stop) })
+}
+
+type fileStamp struct {
+	modTime time.Time
+	size    int64
+}
+
+func (w *Watcher) fileStamps() map[string]fileStamp {
+	stamps := make(map[string]fileStamp)
+	for _, source := range w.sources {
+		if f, ok := source.(fileSource); ok {
+			if info, err := os.Stat(string(f)); err == nil {
+				stamps[string(f)] = fileStamp{info.ModTime(), info.Size()}
+			}
+		}
+	}
+	return stamps
+}
+
+// OnChange calls fn with the settings of the section bound into T, see util.Bind, each time they change.
+// New snapshots whose section cannot be bound are rejected.
+func OnChange[T any](w *Watcher, section string, fn func(T)) {
+	w.Validate(func(c *Config) error {
+		var settings T
+		if err := util.Bind(c.Section(section), &settings); err != nil {
+			return fmt.Errorf("section %s: %w", section, err)
+		}
+		return nil
+	})
+	w.Subscribe(func(old, new *Config) {
+		s := new.Section(section)
+		if reflect.DeepEqual(old.Section(section), s) {
+			return
+		}
+		var settings T
+		if err := util.Bind(s, &settings); err == nil {
+			fn(settings)
+		}
+	})
+}
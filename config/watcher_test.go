@@ -0,0 +1,117 @@
+package config
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"time"
+)
+
+type testLogSettings struct {
+	Level string `setting:"level" enum:"info,debug"`
+}
+
+func TestWatcherReload(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	write := func(content string) {
+		if err := ioutil.WriteFile(file, []byte(content), 0600); err != nil {
+			t.Fatal(err)
+		}
+	}
+	write("log: level=info\nserver: port=80")
+
+	w, err := NewWatcher(File(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	var changes []string
+	OnChange(w, LogSection, func(s testLogSettings) { changes = append(changes, s.Level) })
+
+	write("log: level=debug\nserver: port=80")
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	write("log: level=debug\nserver: port=81")
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if len(changes) != 1 || changes[0] != "debug" {
+		t.Errorf("expected one change to debug but got %v", changes)
+	}
+
+	write("log: level=verbose\nserver: port=82")
+	if err := w.Reload(); err == nil {
+		t.Error("expected validation error")
+	}
+	if port := w.Current().Section(ServerSection)["port"]; port != "81" {
+		t.Errorf("expected previous snapshot to be kept but got port %s", port)
+	}
+}
+
+func TestWatcherPolling(t *testing.T) {
+	file := filepath.Join(t.TempDir(), "config.yaml")
+	if err := ioutil.WriteFile(file, []byte("log: level=info"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	w, err := NewWatcher(File(file))
+	if err != nil {
+		t.Fatal(err)
+	}
+	reloaded := make(chan *Config, 1)
+	w.Subscribe(func(old, new *Config) { reloaded <- new })
+	w.Start(10 * time.Millisecond)
+	defer w.Stop()
+
+	if err := ioutil.WriteFile(file, []byte("log: level=debug"), 0600); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case c := <-reloaded:
+		if level := c.Section(LogSection)["level"]; level != "debug" {
+			t.Errorf("expected debug level but got %s", level)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected reload on file change")
+	}
+}
+
+func TestWatcherValidatorsRunUnlocked(t *testing.T) {
+	level := "info"
+	w, err := NewWatcher(SourceFunc(func() (map[string]Section, error) {
+		return map[string]Section{LogSection: {"level": level}}, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	validating, release := make(chan struct{}), make(chan struct{})
+	w.Validate(func(c *Config) error {
+		if w.Current().Section(LogSection)["level"] != "info" {
+			t.Error("expected the previous snapshot while validating")
+		}
+		close(validating)
+		<-release
+		return nil
+	})
+
+	level = "debug"
+	reloaded := make(chan error)
+	go func() { reloaded <- w.Reload() }()
+	<-validating
+	current := make(chan *Config)
+	go func() { current <- w.Current() }()
+	select {
+	case c := <-current:
+		if c.Section(LogSection)["level"] != "info" {
+			t.Error("expected the previous snapshot while validating")
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("expected Current not to wait for the validators")
+	}
+	close(release)
+	if err := <-reloaded; err != nil {
+		t.Fatal(err)
+	}
+	if w.Current().Section(LogSection)["level"] != "debug" {
+		t.Error("expected the new snapshot after validation")
+	}
+}
@@ -1,8 +1,13 @@
 package cqs
 
 import (
+	"bytes"
 	"context"
+	"log/slog"
+	"strings"
 	"testing"
+
+	"github.com/jedrp/go-core/log"
 )
 
 type testCommand struct{}
@@ -68,3 +73,56 @@ func TestHandlerDuplicated(t *testing.T) {
 		t.Errorf("expected duplicated error")
 	}
 }
+
+type tenantHandler struct{}
+
+func (*tenantHandler) Handle(ctx context.Context, command *testCommand) (*testCommandResponse, error) {
+	return &testCommandResponse{Value: 2}, nil
+}
+
+func TestTenantHandlerResolution(t *testing.T) {
+	ResetDispatcherSetting()
+	ctx := context.Background()
+	tenantCtx := WithTenant(ctx, "tenant-a")
+	if err := RegisterHandler[*testCommand, *testCommandResponse](ctx, &testHandler{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterHandler[*testCommand, *testCommandResponse](tenantCtx, &tenantHandler{}); err != nil {
+		t.Fatal(err)
+	}
+	if err := RegisterHandler[*testCommand, *testCommandResponse](tenantCtx, &tenantHandler{}); err == nil {
+		t.Error("expected duplicated error for the same tenant")
+	}
+
+	tt := []struct {
+		ctx      context.Context
+		expected int
+	}{
+		{ctx: ctx, expected: 1},
+		{ctx: tenantCtx, expected: 2},
+		{ctx: WithTenant(ctx, "tenant-b"), expected: 1},
+	}
+	for i, tc := range tt {
+		r, err := Send[*testCommand, *testCommandResponse](tc.ctx, &testCommand{})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if r.Value != tc.expected {
+			t.Errorf("tc #%d, expected %v but got %v", i, tc.expected, r.Value)
+		}
+	}
+}
+
+func TestConfigureLogger(t *testing.T) {
+	ResetDispatcherSetting()
+	var buf bytes.Buffer
+	ConfigureLogger(log.NewSlogLogger(slog.NewJSONHandler(&buf, nil)).Named("cqs"))
+	defer ConfigureLogger(nil)
+
+	if _, err := Send[*testCommand, *testCommandResponse](context.Background(), &testCommand{}); err != ErrHandlerNotFound {
+		t.Fatalf("expected ErrHandlerNotFound but got %v", err)
+	}
+	if !strings.Contains(buf.String(), `"Component":"cqs"`) || !strings.Contains(buf.String(), "can't find handler") {
+		t.Errorf("expected the error logged by the configured logger but got %s", buf.String())
+	}
+}
@@ -14,6 +14,9 @@ import (
 type memoryDispatcher struct {
 	maxLatencyInMillisecond time.Duration
 	handlersMap             map[string]interface{}
+	tenantHandlersMap       map[string]map[string]interface{}
+	exporter                SpanExporter
+	logger                  log.Logger
 }
 
 var (
@@ -22,6 +25,8 @@ var (
 	defaultDispatcher        = &memoryDispatcher{
 		maxLatencyInMillisecond: 0,
 		handlersMap:             make(map[string]interface{}),
+		tenantHandlersMap:       make(map[string]map[string]interface{}),
+		logger:                  defaultLogger(),
 	}
 )
 
@@ -29,29 +34,92 @@ func ConfigureTimeOut(timeoutInMillisecond int) {
 	defaultDispatcher.maxLatencyInMillisecond = time.Duration(timeoutInMillisecond) * time.Millisecond
 }
 
+// ConfigureLogger sets the logger of the dispatch logs, nil restores the default "cqs" logger
+// whose level is set by level.cqs of the "log" section
+func ConfigureLogger(logger log.Logger) {
+	if logger == nil {
+		logger = defaultLogger()
+	}
+	defaultDispatcher.logger = logger
+}
+
+func defaultLogger() log.Logger {
+	return log.DefaultLogger.Named("cqs")
+}
+
+// RegisterRequestHandlerFactory registers the factory as default handler,
+// or as the handler of the tenant when ctx is scoped with WithTenant
 func RegisterRequestHandlerFactory[TRequest Request, TResponse Response](ctx context.Context, factory HandlerFactory[TRequest, TResponse]) error {
-	return registerRequestHandler[TRequest, TResponse](factory)
+	return registerRequestHandler[TRequest, TResponse](ctx, factory)
 }
 
+// RegisterHandler registers the handler as default handler,
+// or as the handler of the tenant when ctx is scoped with WithTenant
 func RegisterHandler[TRequest Request, TResponse Response](ctx context.Context, handler Handler[TRequest, TResponse]) error {
-	return registerRequestHandler[TRequest, TResponse](handler)
+	return registerRequestHandler[TRequest, TResponse](ctx, handler)
 }
 
-func registerRequestHandler[TRequest Request, TResponse Response](handler any) error {
+func registerRequestHandler[TRequest Request, TResponse Response](ctx context.Context, handler any) error {
 	r := *new(TRequest)
-	_, exist := defaultDispatcher.handlersMap[r.HandlerID()]
+	handlersMap := defaultDispatcher.handlersMap
+	tenantID := TenantFromContext(ctx)
+	if tenantID != "" {
+		handlersMap = defaultDispatcher.tenantHandlersMap[tenantID]
+		if handlersMap == nil {
+			handlersMap = make(map[string]interface{})
+			defaultDispatcher.tenantHandlersMap[tenantID] = handlersMap
+		}
+	}
+	_, exist := handlersMap[r.HandlerID()]
 	if exist {
 		typeName := reflect.TypeOf(r).String()
-		// each request in request/response strategy should have just one handler
+		// each request in request/response strategy should have just one handler per tenant
+		if tenantID != "" {
+			return fmt.Errorf("duplicated executer registration detected of type: %s handlerID: %s tenantID: %s", typeName, r.HandlerID(), tenantID)
+		}
 		return fmt.Errorf("duplicated executer registration detected of type: %s handlerID: %s", typeName, r.HandlerID())
 	}
 
-	defaultDispatcher.handlersMap[r.HandlerID()] = handler
+	handlersMap[r.HandlerID()] = handler
 
 	return nil
 }
 
+// resolveHandler returns the handler registered for the tenant of ctx, falling back to the default one
+func resolveHandler(ctx context.Context, handlerID string) (any, bool) {
+	if tenantID := TenantFromContext(ctx); tenantID != "" {
+		if hv, ok := defaultDispatcher.tenantHandlersMap[tenantID][handlerID]; ok {
+			return hv, true
+		}
+	}
+	hv, ok := defaultDispatcher.handlersMap[handlerID]
+	return hv, ok
+}
+
 func Send[TRequest Request, TResponse Response](ctx context.Context, request TRequest) (TResponse, error) {
+	handlerID := request.HandlerID()
+	ctx = log.ContextWithFields(ctx, map[string]interface{}{log.HandlerID: handlerID})
+	exporter := defaultDispatcher.exporter
+	if exporter == nil {
+		return send[TRequest, TResponse](ctx, request)
+	}
+
+	ctx, span := startSpan(ctx, handlerID)
+	span.Attributes[AttributeHandlerID] = handlerID
+	span.Attributes[AttributeRequestType] = reflect.TypeOf(request).String()
+	if tenantID := TenantFromContext(ctx); tenantID != "" {
+		span.Attributes[AttributeTenantID] = tenantID
+	}
+	defer span.end(exporter)
+
+	response, err := send[TRequest, TResponse](ctx, request)
+	if err != nil {
+		span.recordError(err)
+	}
+	return response, err
+}
+
+func send[TRequest Request, TResponse Response](ctx context.Context, request TRequest) (TResponse, error) {
 	var (
 		cancel context.CancelFunc
 	)
@@ -60,23 +128,24 @@ func Send[TRequest Request, TResponse Response](ctx context.Context, request TRe
 		defer cancel()
 	}
 	handlerID := request.HandlerID()
-	if log.DefaultLogger.IsLevelEnabled(logrus.DebugLevel) {
-		defer elapsed(ctx, "dispatching "+request.HandlerID(), log.DefaultLogger)()
+	logger := defaultDispatcher.logger
+	if logger.IsLevelEnabled(logrus.DebugLevel) {
+		defer elapsed(ctx, "dispatching "+request.HandlerID(), logger)()
 	}
-	if hv, ok := defaultDispatcher.handlersMap[handlerID]; ok {
+	if hv, ok := resolveHandler(ctx, handlerID); ok {
 		h, err := buildHandler[TRequest, TResponse](hv)
 		if err != nil {
 			return *new(TResponse), err
 		}
 		response, err := h.Handle(ctx, request)
 		if err != nil {
-			log.CreateRequestLogEntryFromContext(ctx, log.DefaultLogger).Error(err)
+			log.CreateRequestLogEntryFromContext(ctx, logger).Error(err)
 		}
 		return response, err
 	}
 
 	msg := fmt.Sprintf("MemoryDispatcher can't find handler for type: %s handlerID: %s", reflect.TypeOf(request).String(), handlerID)
-	log.CreateRequestLogEntryFromContext(ctx, log.DefaultLogger).Error(msg)
+	log.CreateRequestLogEntryFromContext(ctx, logger).Error(msg)
 	return *new(TResponse), ErrHandlerNotFound
 }
 
@@ -103,4 +172,5 @@ func elapsed(ctx context.Context, what string, logger log.Logger) func() {
 
 func ResetDispatcherSetting() {
 	defaultDispatcher.handlersMap = make(map[string]interface{})
+	defaultDispatcher.tenantHandlersMap = make(map[string]map[string]interface{})
 }
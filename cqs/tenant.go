@@ -0,0 +1,21 @@
+package cqs
+
+import (
+	"context"
+
+	"github.com/jedrp/go-core/log"
+)
+
+// AttributeTenantID span attribute holding the tenant of the dispatch
+const AttributeTenantID = "tenant.id"
+
+// WithTenant returns a context scoped to the tenant, handlers registered with it
+// only serve that tenant and Send resolves them before the default handlers.
+func WithTenant(ctx context.Context, tenantID string) context.Context {
+	return log.ContextWithFields(ctx, map[string]interface{}{log.TenantID: tenantID})
+}
+
+// TenantFromContext returns the tenant set by WithTenant, empty if none
+func TenantFromContext(ctx context.Context) string {
+	return log.TenantIDFromContext(ctx)
+}
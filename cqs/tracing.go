@@ -0,0 +1,188 @@
+package cqs
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/jedrp/go-core/log"
+	"github.com/jedrp/go-core/result"
+)
+
+// Span attribute keys, named after the OpenTelemetry semantic conventions where one exists.
+const (
+	AttributeHandlerID     = "cqs.handler_id"
+	AttributeRequestType   = "cqs.request_type"
+	AttributeRequestID     = "request.id"
+	AttributeCorrelationID = "correlation.id"
+	AttributeErrorCode     = "error.code"
+	AttributeExceptionMsg  = "exception.message"
+)
+
+// SpanStatus mirrors the OpenTelemetry span status codes
+type SpanStatus int
+
+const (
+	SpanStatusUnset SpanStatus = iota
+	SpanStatusOK
+	SpanStatusError
+)
+
+// TraceID is a 16 bytes W3C trace identifier
+type TraceID [16]byte
+
+// SpanID is an 8 bytes W3C span identifier
+type SpanID [8]byte
+
+func (t TraceID) String() string { return hex.EncodeToString(t[:]) }
+
+func (t TraceID) IsValid() bool { return t != TraceID{} }
+
+func (s SpanID) String() string { return hex.EncodeToString(s[:]) }
+
+func (s SpanID) IsValid() bool { return s != SpanID{} }
+
+// SpanContext identifies a span across process boundaries
+type SpanContext struct {
+	TraceID TraceID
+	SpanID  SpanID
+}
+
+func (sc SpanContext) IsValid() bool {
+	return sc.TraceID.IsValid() && sc.SpanID.IsValid()
+}
+
+// SpanEvent is a timestamped annotation of a span, e.g. the "exception" event
+type SpanEvent struct {
+	Name       string
+	Time       time.Time
+	Attributes map[string]interface{}
+}
+
+// Span describes one dispatch, exported when the dispatch is completed
+type Span struct {
+	Name          string
+	SpanContext   SpanContext
+	Parent        SpanContext
+	StartTime     time.Time
+	EndTime       time.Time
+	Attributes    map[string]interface{}
+	Events        []SpanEvent
+	Status        SpanStatus
+	StatusMessage string
+}
+
+// SpanExporter receives every completed span
+type SpanExporter interface {
+	ExportSpan(span *Span)
+}
+
+// InMemoryExporter keeps exported spans in memory, useful for tests
+type InMemoryExporter struct {
+	mux   sync.Mutex
+	spans []*Span
+}
+
+func NewInMemoryExporter() *InMemoryExporter {
+	return &InMemoryExporter{}
+}
+
+func (e *InMemoryExporter) ExportSpan(span *Span) {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.spans = append(e.spans, span)
+}
+
+// Spans returns the exported spans in completion order
+func (e *InMemoryExporter) Spans() []*Span {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	spans := make([]*Span, len(e.spans))
+	copy(spans, e.spans)
+	return spans
+}
+
+func (e *InMemoryExporter) Reset() {
+	e.mux.Lock()
+	defer e.mux.Unlock()
+	e.spans = nil
+}
+
+type spanContextKey struct{}
+
+// ContextWithSpanContext sets the parent span context for dispatches made with the returned context,
+// transports use it to continue a trace started by a remote caller.
+func ContextWithSpanContext(ctx context.Context, sc SpanContext) context.Context {
+	return context.WithValue(ctx, spanContextKey{}, sc)
+}
+
+// SpanContextFromContext returns the span context of the current dispatch, if any
+func SpanContextFromContext(ctx context.Context) (SpanContext, bool) {
+	sc, ok := ctx.Value(spanContextKey{}).(SpanContext)
+	return sc, ok && sc.IsValid()
+}
+
+// ConfigureTracing sets the exporter receiving dispatch spans, nil disables tracing
+func ConfigureTracing(exporter SpanExporter) {
+	defaultDispatcher.exporter = exporter
+}
+
+func startSpan(ctx context.Context, name string) (context.Context, *Span) {
+	span := &Span{
+		Name:       name,
+		StartTime:  time.Now(),
+		Attributes: make(map[string]interface{}),
+	}
+	if parent, ok := SpanContextFromContext(ctx); ok {
+		span.Parent = parent
+		span.SpanContext.TraceID = parent.TraceID
+	} else {
+		rand.Read(span.SpanContext.TraceID[:])
+	}
+	rand.Read(span.SpanContext.SpanID[:])
+
+	if v := log.RequestIDFromContext(ctx); v != "" {
+		span.Attributes[AttributeRequestID] = v
+	}
+	if v := log.CorrelationIDFromContext(ctx); v != "" {
+		span.Attributes[AttributeCorrelationID] = v
+	}
+	ctx = log.ContextWithFields(ctx, map[string]interface{}{
+		log.TraceID: span.SpanContext.TraceID.String(),
+		log.SpanID:  span.SpanContext.SpanID.String(),
+	})
+	return ContextWithSpanContext(ctx, span.SpanContext), span
+}
+
+func (s *Span) recordError(err error) {
+	s.Status = SpanStatusError
+	s.StatusMessage = err.Error()
+	s.Attributes[AttributeErrorCode] = string(errorCode(err))
+	s.Events = append(s.Events, SpanEvent{
+		Name: "exception",
+		Time: time.Now(),
+		Attributes: map[string]interface{}{
+			AttributeExceptionMsg: err.Error(),
+		},
+	})
+}
+
+func (s *Span) end(exporter SpanExporter) {
+	s.EndTime = time.Now()
+	if s.Status == SpanStatusUnset {
+		s.Status = SpanStatusOK
+	}
+	exporter.ExportSpan(s)
+}
+
+func errorCode(err error) result.ErrorCode {
+	switch {
+	case errors.Is(err, ErrHandlerNotFound), errors.Is(err, ErrHandlerTypeNotSupport):
+		return result.Unimplemented
+	default:
+		return result.CodeOf(err)
+	}
+}
@@ -0,0 +1,144 @@
+package cqs
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/jedrp/go-core/log"
+)
+
+type outerCommand struct{}
+
+func (c *outerCommand) HandlerID() string {
+	return "outerHandler"
+}
+
+type outerHandler struct{}
+
+func (*outerHandler) Handle(ctx context.Context, command *outerCommand) (*testCommandResponse, error) {
+	return Send[*testCommand, *testCommandResponse](ctx, &testCommand{})
+}
+
+type failingCommand struct{}
+
+func (c *failingCommand) HandlerID() string {
+	return "failingHandler"
+}
+
+type failingHandler struct{}
+
+func (*failingHandler) Handle(ctx context.Context, command *failingCommand) (*testCommandResponse, error) {
+	return nil, errors.New("boom")
+}
+
+func TestTracingNestedSend(t *testing.T) {
+	ResetDispatcherSetting()
+	exporter := NewInMemoryExporter()
+	ConfigureTracing(exporter)
+	defer ConfigureTracing(nil)
+
+	ctx := context.WithValue(context.Background(), log.RequestID, "req-1")
+	RegisterHandler[*testCommand, *testCommandResponse](ctx, &testHandler{})
+	RegisterHandler[*outerCommand, *testCommandResponse](ctx, &outerHandler{})
+
+	if _, err := Send[*outerCommand, *testCommandResponse](ctx, &outerCommand{}); err != nil {
+		t.Fatal(err)
+	}
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans but got %d", len(spans))
+	}
+	inner, outer := spans[0], spans[1]
+	if inner.Name != "testHandler" || outer.Name != "outerHandler" {
+		t.Errorf("unexpected span names %s, %s", inner.Name, outer.Name)
+	}
+	if inner.SpanContext.TraceID != outer.SpanContext.TraceID {
+		t.Error("nested span should share the trace id")
+	}
+	if inner.Parent != outer.SpanContext {
+		t.Error("nested span should be a child of the outer span")
+	}
+	if outer.Parent.IsValid() {
+		t.Error("outer span should be a root span")
+	}
+	if inner.Attributes[AttributeRequestID] != "req-1" {
+		t.Errorf("expected request id attribute but got %v", inner.Attributes[AttributeRequestID])
+	}
+	if outer.Status != SpanStatusOK {
+		t.Errorf("expected ok status but got %v", outer.Status)
+	}
+}
+
+func TestTracingRecordsError(t *testing.T) {
+	ResetDispatcherSetting()
+	exporter := NewInMemoryExporter()
+	ConfigureTracing(exporter)
+	defer ConfigureTracing(nil)
+
+	ctx := context.Background()
+	RegisterHandler[*failingCommand, *testCommandResponse](ctx, &failingHandler{})
+	Send[*failingCommand, *testCommandResponse](ctx, &failingCommand{})
+	Send[*testCommand, *testCommandResponse](ctx, &testCommand{})
+
+	spans := exporter.Spans()
+	if len(spans) != 2 {
+		t.Fatalf("expected 2 spans but got %d", len(spans))
+	}
+	if spans[0].Status != SpanStatusError || spans[0].StatusMessage != "boom" {
+		t.Errorf("expected error status but got %v %s", spans[0].Status, spans[0].StatusMessage)
+	}
+	if len(spans[0].Events) != 1 || spans[0].Events[0].Name != "exception" {
+		t.Error("expected an exception event")
+	}
+	if spans[1].Attributes[AttributeErrorCode] != "Unimplemented" {
+		t.Errorf("expected Unimplemented error code but got %v", spans[1].Attributes[AttributeErrorCode])
+	}
+}
+
+type fieldsCommand struct{}
+
+func (c *fieldsCommand) HandlerID() string {
+	return "fieldsHandler"
+}
+
+type fieldsHandler struct {
+	fields map[string]interface{}
+}
+
+func (h *fieldsHandler) Handle(ctx context.Context, command *fieldsCommand) (*testCommandResponse, error) {
+	h.fields = log.FieldsFromContext(ctx)
+	return &testCommandResponse{}, nil
+}
+
+func TestSendAddsLogFields(t *testing.T) {
+	ResetDispatcherSetting()
+	exporter := NewInMemoryExporter()
+	ConfigureTracing(exporter)
+	defer ConfigureTracing(nil)
+
+	handler := &fieldsHandler{}
+	ctx := log.ContextWithRequestID(WithTenant(context.Background(), "tenant-1"), "req-1")
+	RegisterHandler[*fieldsCommand, *testCommandResponse](context.Background(), handler)
+
+	if _, err := Send[*fieldsCommand, *testCommandResponse](ctx, &fieldsCommand{}); err != nil {
+		t.Fatal(err)
+	}
+	span := exporter.Spans()[0]
+	expected := map[string]interface{}{
+		log.RequestID: "req-1",
+		log.TenantID:  "tenant-1",
+		log.HandlerID: "fieldsHandler",
+		log.TraceID:   span.SpanContext.TraceID.String(),
+		log.SpanID:    span.SpanContext.SpanID.String(),
+	}
+	for k, v := range expected {
+		if handler.fields[k] != v {
+			t.Errorf("expected field %s=%v but got %v", k, v, handler.fields[k])
+		}
+	}
+	if ctx.Value(log.RequestID) != "req-1" {
+		t.Error("expected the request id under the legacy context key")
+	}
+}
@@ -0,0 +1,34 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+
+	"github.com/jedrp/go-core/result"
+	"google.golang.org/grpc"
+)
+
+// UnaryServerErrorInterceptor converts a *result.Error returned by the handler to a gRPC status
+// carrying its code, details and the request ID
+func UnaryServerErrorInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, toRPCError(ctx, err)
+	}
+}
+
+// StreamServerErrorInterceptor converts a *result.Error returned by the handler to a gRPC status
+// carrying its code, details and the request ID
+func StreamServerErrorInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		return toRPCError(stream.Context(), handler(srv, stream))
+	}
+}
+
+func toRPCError(ctx context.Context, err error) error {
+	var e *result.Error
+	if err != nil && errors.As(err, &e) {
+		return result.GetRPCErrorWithContext(ctx, e)
+	}
+	return err
+}
@@ -0,0 +1,122 @@
+package grpc
+
+import (
+	"context"
+	"time"
+
+	logcore "github.com/jedrp/go-core/log"
+	"github.com/jedrp/go-core/result"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/types/known/emptypb"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+// LogLevelServiceName full name of the log level admin service
+const LogLevelServiceName = "gocore.admin.v1.LogLevelService"
+
+// LogLevelServiceServer changes the log levels of the process, see logcore.SetLevel.
+// The levels are returned as {"levels": [{"name": "", "level": "info"}, {"name": "cqs", "level": "debug", "revertAt": "..."}]}
+type LogLevelServiceServer interface {
+	// GetLevels lists the levels
+	GetLevels(context.Context, *emptypb.Empty) (*structpb.Struct, error)
+	// SetLevel changes a level from {"name": "cqs", "level": "debug", "revertAfter": "15m"}, temporarily when revertAfter is set
+	SetLevel(context.Context, *structpb.Struct) (*structpb.Struct, error)
+	// ResetLevel restores the configured level of {"name": "cqs"}
+	ResetLevel(context.Context, *structpb.Struct) (*structpb.Struct, error)
+}
+
+// RegisterLogLevelService registers the log level admin service on s,
+// it changes the levels of the whole process and must be served on an admin port or behind authorization
+func RegisterLogLevelService(s *grpc.Server) {
+	s.RegisterService(&logLevelServiceDesc, logLevelService{})
+}
+
+type logLevelService struct{}
+
+func (logLevelService) GetLevels(ctx context.Context, _ *emptypb.Empty) (*structpb.Struct, error) {
+	return logLevels()
+}
+
+func (logLevelService) SetLevel(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	fields := req.GetFields()
+	v := result.NewValidation()
+	level, err := logrus.ParseLevel(fields["level"].GetStringValue())
+	v.Check(err == nil, "level", "enum", "level must be one of trace, debug, info, warn, error, fatal, panic", nil)
+	var revertAfter time.Duration
+	if s := fields["revertAfter"].GetStringValue(); s != "" {
+		revertAfter, err = time.ParseDuration(s)
+		v.Check(err == nil && revertAfter > 0, "revertAfter", "duration", "revertAfter must be a positive duration, e.g. 15m", nil)
+	}
+	if err := v.Err(); err != nil {
+		return nil, toRPCError(ctx, err)
+	}
+	logcore.SetLevel(fields["name"].GetStringValue(), level, revertAfter)
+	return logLevels()
+}
+
+func (logLevelService) ResetLevel(ctx context.Context, req *structpb.Struct) (*structpb.Struct, error) {
+	logcore.ResetLevel(req.GetFields()["name"].GetStringValue())
+	return logLevels()
+}
+
+func logLevels() (*structpb.Struct, error) {
+	settings := logcore.Levels()
+	levels := make([]interface{}, 0, len(settings))
+	for _, s := range settings {
+		l := map[string]interface{}{"name": s.Name, "level": s.Level.String()}
+		if !s.RevertAt.IsZero() {
+			l["revertAt"] = s.RevertAt.Format(time.RFC3339)
+		}
+		levels = append(levels, l)
+	}
+	return structpb.NewStruct(map[string]interface{}{"levels": levels})
+}
+
+var logLevelServiceDesc = grpc.ServiceDesc{
+	ServiceName: LogLevelServiceName,
+	HandlerType: (*LogLevelServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetLevels",
+			Handler: func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+				in := new(emptypb.Empty)
+				if err := dec(in); err != nil {
+					return nil, err
+				}
+				handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+					return srv.(LogLevelServiceServer).GetLevels(ctx, req.(*emptypb.Empty))
+				}
+				if interceptor == nil {
+					return handler(ctx, in)
+				}
+				return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + LogLevelServiceName + "/GetLevels"}, handler)
+			},
+		},
+		{
+			MethodName: "SetLevel",
+			Handler:    structHandler("SetLevel", LogLevelServiceServer.SetLevel),
+		},
+		{
+			MethodName: "ResetLevel",
+			Handler:    structHandler("ResetLevel", LogLevelServiceServer.ResetLevel),
+		},
+	},
+	Streams: []grpc.StreamDesc{},
+}
+
+func structHandler(method string, call func(LogLevelServiceServer, context.Context, *structpb.Struct) (*structpb.Struct, error)) func(interface{}, context.Context, func(interface{}) error, grpc.UnaryServerInterceptor) (interface{}, error) {
+	return func(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+		in := new(structpb.Struct)
+		if err := dec(in); err != nil {
+			return nil, err
+		}
+		handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+			return call(srv.(LogLevelServiceServer), ctx, req.(*structpb.Struct))
+		}
+		if interceptor == nil {
+			return handler(ctx, in)
+		}
+		return interceptor(ctx, in, &grpc.UnaryServerInfo{Server: srv, FullMethod: "/" + LogLevelServiceName + "/" + method}, handler)
+	}
+}
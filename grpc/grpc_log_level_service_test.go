@@ -0,0 +1,50 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	logcore "github.com/jedrp/go-core/log"
+	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/structpb"
+)
+
+func TestLogLevelService(t *testing.T) {
+	t.Cleanup(func() { logcore.ResetLevel("grpctest") })
+	tt := []struct {
+		req   map[string]interface{}
+		code  codes.Code
+		level logrus.Level
+	}{
+		{req: map[string]interface{}{"name": "grpctest", "level": "trace"}, code: codes.OK, level: logrus.TraceLevel},
+		{req: map[string]interface{}{"name": "grpctest", "level": "verbose"}, code: codes.InvalidArgument, level: logrus.TraceLevel},
+		{req: map[string]interface{}{"name": "grpctest", "level": "info", "revertAfter": "soon"}, code: codes.InvalidArgument, level: logrus.TraceLevel},
+		{req: map[string]interface{}{"name": "grpctest", "level": "warn", "revertAfter": "1h"}, code: codes.OK, level: logrus.WarnLevel},
+	}
+	for _, tc := range tt {
+		req, err := structpb.NewStruct(tc.req)
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = logLevelService{}.SetLevel(context.Background(), req)
+		if c := status.Code(err); c != tc.code {
+			t.Errorf("%v: expected %v but got %v", tc.req, tc.code, err)
+		}
+		if l := logcore.GetLevel("grpctest"); l != tc.level {
+			t.Errorf("%v: expected level %s but got %s", tc.req, tc.level, l)
+		}
+	}
+
+	reset, _ := structpb.NewStruct(map[string]interface{}{"name": "grpctest"})
+	resp, err := logLevelService{}.ResetLevel(context.Background(), reset)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, v := range resp.Fields["levels"].GetListValue().GetValues() {
+		if v.GetStructValue().Fields["name"].GetStringValue() == "grpctest" {
+			t.Error("expected the reset level not to be listed")
+		}
+	}
+}
@@ -17,7 +17,7 @@ func UnaryServerPanicInterceptor(logger log.Logger) grpc.UnaryServerInterceptor
 		defer func() {
 			if r := recover(); r != nil {
 				if logger != nil {
-					log.CreateRequestLogEntryFromContext(ctx, logger).Error(r, string(debug.Stack()))
+					log.CreateRequestLogEntryFromContext(log.ContextWithFields(ctx, map[string]interface{}{log.Stack: string(debug.Stack())}), logger).Error(r)
 					err = status.Errorf(codes.Internal, "%v", r)
 				}
 			}
@@ -32,7 +32,7 @@ func getRecoveryHandlerFuncContextHandler(logger log.Logger) grpc_recovery.Recov
 	return grpc_recovery.RecoveryHandlerFuncContext(
 		func(ctx context.Context, p interface{}) error {
 			if logger != nil {
-				log.CreateRequestLogEntryFromContext(ctx, logger).Error(p, string(debug.Stack()))
+				log.CreateRequestLogEntryFromContext(log.ContextWithFields(ctx, map[string]interface{}{log.Stack: string(debug.Stack())}), logger).Error(p)
 			}
 			return nil
 		},
@@ -29,7 +29,7 @@ func setUpRequestInfoToContext(baseCtx context.Context) (context.Context, error)
 	if ok {
 		corIDs := md.Get(log.CorrelationIDHeaderKey)
 		if len(corIDs) > 0 {
-			baseCtx = context.WithValue(baseCtx, log.CorrelationID, corIDs[0])
+			baseCtx = log.ContextWithCorrelationID(baseCtx, corIDs[0])
 		}
 		var requestId string
 		requestIDs := md.Get(log.RequestIDHeaderKey)
@@ -39,7 +39,7 @@ func setUpRequestInfoToContext(baseCtx context.Context) (context.Context, error)
 		if requestId == "" {
 			requestId = uuid.NewV4().String()
 		}
-		baseCtx = context.WithValue(baseCtx, log.RequestID, requestId)
+		baseCtx = log.ContextWithRequestID(baseCtx, requestId)
 
 		return baseCtx, nil
 	}
@@ -5,12 +5,12 @@ import (
 	"log"
 	"net"
 	"os"
-	"strconv"
 	"time"
 
 	strfmt "github.com/go-openapi/strfmt"
 	grpc_middleware "github.com/grpc-ecosystem/go-grpc-middleware"
 	grpc_recovery "github.com/grpc-ecosystem/go-grpc-middleware/recovery"
+	"github.com/jedrp/go-core/config"
 	logcore "github.com/jedrp/go-core/log"
 	"github.com/jedrp/go-core/util"
 	flags "github.com/jessevdk/go-flags"
@@ -24,48 +24,45 @@ type ServicesRegistrationFunc func(*grpc.Server)
 // Server wrapper object to run grpc services
 // setting string : SERVER_CONFIG|server-config="host=0.0.0.0;port=80;tlsCert=;tlsCertKey=;"
 type Server struct {
-	SettingStr string `long:"server-config" description:"the server setting string" env:"SERVER_CONFIG" json:"settingStr,omitempty"`
-	host       string
-	port       int
+	SettingStr string `json:"settingStr,omitempty"`
+	settings   serverSettings
 	logger     logcore.Logger
 	grpcServer *grpc.Server
 }
 
-const (
-	tlsCert    = "tlsCert"
-	tlsCertKey = "tlsCertKey"
-	host       = "host"
-	port       = "port"
-)
+type serverSettings struct {
+	Host       string `setting:"host" default:"127.0.0.1" description:"the interface to listen on"`
+	Port       int    `setting:"port" description:"the port to listen on"`
+	TLSCert    string `setting:"tlsCert" description:"the certificate file"`
+	TLSCertKey string `setting:"tlsCertKey" description:"the certificate key file"`
+}
 
+func init() {
+	config.RegisterSchema(config.ServerSection, util.SchemaOf(serverSettings{}))
+}
+
+// NewServer creates the server from the "server" section of config.Default()
 func NewServer(servicesRegistrationFunc ServicesRegistrationFunc, logger logcore.Logger) *Server {
+	return NewServerWithSettings(servicesRegistrationFunc, logger, config.Default().Section(config.ServerSection))
+}
 
+// NewServerWithSettings creates the server from the given settings, see the setting string of Server
+func NewServerWithSettings(servicesRegistrationFunc ServicesRegistrationFunc, logger logcore.Logger, settings config.Section) *Server {
 	server := &Server{
-		logger: logger,
+		SettingStr: settings.Redacted(),
+		logger:     logger,
 	}
-	parser := flags.NewParser(server, flags.IgnoreUnknown)
-	ParseConfig(parser)
 
 	if server.SettingStr == "" {
 		logger.Warn("empty server setting string")
 	} else {
 		logger.Infof("server start with setting string: %v", server.SettingStr)
 	}
-	config, err := util.GetConfig(server.SettingStr)
-	if err != nil {
+	if err := util.Bind(settings, &server.settings); err != nil {
 		panic(err)
 	}
-	cert := config[tlsCert]
-	certKey := config[tlsCertKey]
-	if v, found := config[host]; found {
-		server.host = v
-	} else {
-		server.host = "127.0.0.1"
-	}
-	if v, found := config[port]; found {
-		server.port, _ = strconv.Atoi(v)
-	} else {
-		server.port = 0
+	for _, key := range util.UnknownKeys(settings, util.SchemaOf(serverSettings{})) {
+		logger.Warnf("unknown server setting %s is ignored", key)
 	}
 
 	formats := strfmt.Default
@@ -77,17 +74,19 @@ func NewServer(servicesRegistrationFunc ServicesRegistrationFunc, logger logcore
 		PermitWithoutStream: true,
 	}), grpc.UnaryInterceptor(grpc_middleware.ChainUnaryServer(
 		UnaryServerRequestContextInterceptor(),
+		UnaryServerErrorInterceptor(),
 		UnaryServerPanicInterceptor(logger),
 		UnaryValidatorServerInterceptor(formats, logger),
 	)), grpc.StreamInterceptor(grpc_middleware.ChainStreamServer(
 		StreamServerRequestInterceptor(),
+		StreamServerErrorInterceptor(),
 		grpc_recovery.StreamServerInterceptor(
 			grpc_recovery.WithRecoveryHandlerContext(getRecoveryHandlerFuncContextHandler(logger)),
 		),
 		StreamValidatorServerInterceptor(formats, logger),
 	))}
-	if cert != "" || certKey != "" {
-		creds, err := credentials.NewServerTLSFromFile(cert, certKey)
+	if server.settings.TLSCert != "" || server.settings.TLSCertKey != "" {
+		creds, err := credentials.NewServerTLSFromFile(server.settings.TLSCert, server.settings.TLSCertKey)
 		if err != nil {
 			logger.Fatal(err)
 		}
@@ -105,7 +104,7 @@ func NewServer(servicesRegistrationFunc ServicesRegistrationFunc, logger logcore
 }
 
 func (s *Server) Serve() error {
-	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", s.host, s.port))
+	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", s.settings.Host, s.settings.Port))
 	if err != nil {
 		s.logger.Panicf("failed to listen: %v", err)
 	}
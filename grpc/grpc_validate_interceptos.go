@@ -5,9 +5,8 @@ import (
 
 	strfmt "github.com/go-openapi/strfmt"
 	"github.com/jedrp/go-core/log"
+	"github.com/jedrp/go-core/result"
 	"google.golang.org/grpc"
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
 )
 
 type validator interface {
@@ -27,7 +26,7 @@ func UnaryValidatorServerInterceptor(formats strfmt.Registry, logger log.Logger)
 		if v, ok := req.(validator); ok {
 			if err := v.Validate(formats); err != nil {
 				log.CreateRequestLogEntryFromContext(ctx, logger).Errorf("InvalidArgument %s", err.Error())
-				return nil, status.Errorf(codes.InvalidArgument, err.Error())
+				return nil, result.GetRPCErrorWithContext(ctx, result.FromValidationError(err))
 			}
 		}
 		return handler(ctx, req)
@@ -49,7 +48,7 @@ func (s *receiverWrapper) RecvMsg(m interface{}) error {
 	if v, ok := m.(validator); ok {
 		if err := v.Validate(s.formats); err != nil {
 			log.CreateRequestLogEntryFromContext(s.ctx, s.logger).Errorf("InvalidArgument %s", err.Error())
-			return status.Errorf(codes.InvalidArgument, err.Error())
+			return result.GetRPCErrorWithContext(s.ctx, result.FromValidationError(err))
 		}
 	}
 	return nil
@@ -1,14 +1,20 @@
 package jwt
 
 import (
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
 	"net/http"
+	"strings"
 	"sync"
 	"time"
 
 	"github.com/dgrijalva/jwt-go"
+	configcore "github.com/jedrp/go-core/config"
+	"github.com/jedrp/go-core/log"
+	"github.com/jedrp/go-core/result"
+	"github.com/jedrp/go-core/util"
 )
 
 type Jwks struct {
@@ -22,13 +28,21 @@ type JwtValidator struct {
 	cert        string
 	certRenewAt time.Time
 	mux         sync.Mutex
+	// guards Aud, Issuer, JwkAddress and jwks changed by Update
+	settingsMux sync.RWMutex
+}
+
+// Settings setting string of the validator, e.g. "aud=api;issuer=https://idp"
+type Settings struct {
+	Aud    string `setting:"aud" required:"true" description:"the expected audience of the tokens"`
+	Issuer string `setting:"issuer" required:"true" description:"the issuer of the tokens, serving the JWKS"`
 }
 
 func NewJwtValidator(aud string, issuer string) (*JwtValidator, error) {
 	v := &JwtValidator{
 		Aud:        aud,
 		Issuer:     issuer,
-		JwkAddress: fmt.Sprintf("%s/.well-known/openid-configuration/jwks", issuer),
+		JwkAddress: jwkAddress(issuer),
 	}
 	jwks, e := v.GetJwks()
 	v.jwks = jwks
@@ -48,18 +62,125 @@ func (config *JwtValidator) ValidateToken(token string) (*jwt.Token, error) {
 	return jwt.Parse(token, config.ValidationKeyGetter)
 }
 
+// ContextWithSubject returns a context carrying the subject of the validated token as the UserId log field
+func ContextWithSubject(ctx context.Context, token *jwt.Token) context.Context {
+	claims, ok := token.Claims.(jwt.MapClaims)
+	if !ok {
+		return ctx
+	}
+	subject, ok := claims["sub"].(string)
+	if !ok || subject == "" {
+		return ctx
+	}
+	return log.ContextWithFields(ctx, map[string]interface{}{log.UserID: subject})
+}
+
+// Middleware validates the bearer token of the requests and adds its subject to the log fields of the request
+// context, see ContextWithSubject. Requests without a valid token are answered with 401 Unauthenticated.
+func (config *JwtValidator) Middleware(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		header := r.Header.Get("Authorization")
+		bearer := strings.TrimPrefix(header, "Bearer ")
+		if bearer == header || bearer == "" {
+			result.FailWith(result.NewUnauthenticated("missing bearer token")).Write(w, r)
+			return
+		}
+		token, err := config.ValidateToken(bearer)
+		if err != nil {
+			result.FailWith(result.NewUnauthenticated("invalid bearer token: %v", err)).Write(w, r)
+			return
+		}
+		handler.ServeHTTP(w, r.WithContext(ContextWithSubject(r.Context(), token)))
+	})
+}
+
+func jwkAddress(issuer string) string {
+	return fmt.Sprintf("%s/.well-known/openid-configuration/jwks", issuer)
+}
+
+// Update changes the audience and the issuer, the keys of the new issuer are fetched before the change is applied
+func (config *JwtValidator) Update(aud string, issuer string) error {
+	jwks, err := getJwks(jwkAddress(issuer))
+	if err != nil {
+		return err
+	}
+	config.apply(aud, issuer, jwks)
+	return nil
+}
+
+func (config *JwtValidator) apply(aud string, issuer string, jwks *Jwks) {
+	config.settingsMux.Lock()
+	config.Aud = aud
+	config.Issuer = issuer
+	config.JwkAddress = jwkAddress(issuer)
+	config.jwks = jwks
+	config.settingsMux.Unlock()
+
+	config.mux.Lock()
+	config.cert = ""
+	config.certRenewAt = time.Time{}
+	config.mux.Unlock()
+}
+
+// Watch updates the validator when w reloads the section, see Settings.
+// The keys of a new issuer are fetched while the snapshot is validated, so a reload
+// whose issuer does not serve its keys is rejected and the validator keeps its settings.
+func (config *JwtValidator) Watch(w *configcore.Watcher, section string) {
+	var (
+		mux     sync.Mutex
+		fetched = make(map[string]*Jwks)
+	)
+	w.Validate(func(c *configcore.Config) error {
+		var settings Settings
+		if err := util.Bind(c.Section(section), &settings); err != nil {
+			return fmt.Errorf("section %s: %w", section, err)
+		}
+		config.settingsMux.RLock()
+		issuer := config.Issuer
+		config.settingsMux.RUnlock()
+		if settings.Issuer == issuer {
+			return nil
+		}
+		jwks, err := getJwks(jwkAddress(settings.Issuer))
+		if err != nil {
+			return fmt.Errorf("section %s: fetch the keys of issuer %s: %w", section, settings.Issuer, err)
+		}
+		mux.Lock()
+		fetched[settings.Issuer] = jwks
+		mux.Unlock()
+		return nil
+	})
+	configcore.OnChange(w, section, func(settings Settings) {
+		mux.Lock()
+		jwks, found := fetched[settings.Issuer]
+		// keys fetched for snapshots rejected by other validators are dropped too
+		fetched = make(map[string]*Jwks)
+		mux.Unlock()
+		if !found {
+			config.settingsMux.RLock()
+			jwks = config.jwks
+			config.settingsMux.RUnlock()
+		}
+		config.apply(settings.Aud, settings.Issuer, jwks)
+	})
+}
+
 func (config *JwtValidator) ValidationKeyGetter(token *jwt.Token) (interface{}, error) {
+	config.settingsMux.RLock()
+	aud, issuer := config.Aud, config.Issuer
+	config.settingsMux.RUnlock()
+
 	audClaim, _ := token.Claims.(jwt.MapClaims)["aud"]
 	validAud := false
 	if real, ok := audClaim.([]interface{}); ok {
 		for _, v := range real {
-			if v == config.Aud {
+			if v == aud {
 				validAud = true
 			}
 		}
 	} else {
 		if v, ok := audClaim.(interface{}); ok {
-			if v == config.Aud {
+			if v == aud {
 				validAud = true
 			}
 		}
@@ -67,18 +188,17 @@ func (config *JwtValidator) ValidationKeyGetter(token *jwt.Token) (interface{},
 	if !validAud {
 		return token, errors.New("Invalid audience.")
 	}
-	checkIss := token.Claims.(jwt.MapClaims).VerifyIssuer(config.Issuer, false)
+	checkIss := token.Claims.(jwt.MapClaims).VerifyIssuer(issuer, false)
 	if !checkIss {
 		return token, errors.New("Invalid issuer.")
 	}
 
 	cert, err := config.getPemCert(token)
 	if err != nil {
-		panic(err.Error())
+		return token, err
 	}
 
-	result, _ := jwt.ParseRSAPublicKeyFromPEM([]byte(cert))
-	return result, nil
+	return jwt.ParseRSAPublicKeyFromPEM([]byte(cert))
 }
 
 func (config *JwtValidator) getPemCert(token *jwt.Token) (string, error) {
@@ -91,7 +211,10 @@ func (config *JwtValidator) getPemCert(token *jwt.Token) (string, error) {
 		return config.cert, nil
 	}
 
-	for _, v := range config.jwks.Keys {
+	config.settingsMux.RLock()
+	jwks := config.jwks
+	config.settingsMux.RUnlock()
+	for _, v := range jwks.Keys {
 		if token.Header["kid"] == v.Kid {
 			config.cert = "-----BEGIN CERTIFICATE-----\n" + v.X5c[0] + "\n-----END CERTIFICATE-----"
 		}
@@ -106,12 +229,25 @@ func (config *JwtValidator) getPemCert(token *jwt.Token) (string, error) {
 }
 
 func (config *JwtValidator) GetJwks() (*Jwks, error) {
-	resp, err := http.Get(config.JwkAddress)
+	config.settingsMux.RLock()
+	address := config.JwkAddress
+	config.settingsMux.RUnlock()
+	return getJwks(address)
+}
+
+// jwksClient fetches the keys, a reload validating a new issuer waits for it at most the timeout
+var jwksClient = &http.Client{Timeout: 10 * time.Second}
+
+func getJwks(address string) (*Jwks, error) {
+	resp, err := jwksClient.Get(address)
 
 	if err != nil {
 		return nil, err
 	}
 	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("get %s: %s", address, resp.Status)
+	}
 
 	var jwks = &Jwks{}
 	err = json.NewDecoder(resp.Body).Decode(&jwks)
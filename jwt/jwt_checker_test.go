@@ -0,0 +1,137 @@
+package jwt
+
+import (
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/dgrijalva/jwt-go"
+	configcore "github.com/jedrp/go-core/config"
+	"github.com/jedrp/go-core/log"
+)
+
+func newIssuer(t *testing.T, kid string) *httptest.Server {
+	srv := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/.well-known/openid-configuration/jwks" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(`{"keys":[{"kid":"` + kid + `"}]}`))
+	}))
+	t.Cleanup(srv.Close)
+	return srv
+}
+
+func TestWatchReload(t *testing.T) {
+	first, second := newIssuer(t, "first"), newIssuer(t, "second")
+	missing := httptest.NewServer(http.NotFoundHandler())
+	t.Cleanup(missing.Close)
+
+	settings := configcore.Section{"aud": "api", "issuer": first.URL}
+	w, err := configcore.NewWatcher(configcore.SourceFunc(func() (map[string]configcore.Section, error) {
+		return map[string]configcore.Section{"jwt": settings}, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	v, err := NewJwtValidator("api", first.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	v.Watch(w, "jwt")
+
+	settings = configcore.Section{"aud": "api2", "issuer": second.URL}
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	if v.Aud != "api2" || v.Issuer != second.URL || v.jwks.Keys[0].Kid != "second" {
+		t.Errorf("expected the second issuer to be applied but got %s %s %v", v.Aud, v.Issuer, v.jwks.Keys)
+	}
+
+	hung := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	t.Cleanup(hung.Close)
+	timeout := jwksClient.Timeout
+	jwksClient.Timeout = 100 * time.Millisecond
+	t.Cleanup(func() { jwksClient.Timeout = timeout })
+
+	for _, issuer := range []string{missing.URL, "http://127.0.0.1:0", hung.URL} {
+		settings = configcore.Section{"aud": "api3", "issuer": issuer}
+		if err := w.Reload(); err == nil {
+			t.Errorf("expected issuer %s to be rejected", issuer)
+		}
+		if v.Aud != "api2" || v.Issuer != second.URL || w.Current().Section("jwt")["issuer"] != second.URL {
+			t.Errorf("expected the reload of issuer %s to be rejected but got %s %s", issuer, v.Aud, v.Issuer)
+		}
+	}
+}
+
+func TestMiddleware(t *testing.T) {
+	key, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{SerialNumber: big.NewInt(1), Subject: pkix.Name{CommonName: "issuer"}, NotAfter: time.Now().Add(time.Hour)}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	issuer := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"keys":[{"kid":"key","x5c":["` + base64.StdEncoding.EncodeToString(der) + `"]}]}`))
+	}))
+	t.Cleanup(issuer.Close)
+	v, err := NewJwtValidator("api", issuer.URL)
+	if err != nil {
+		t.Fatal(err)
+	}
+	sign := func(claims jwt.MapClaims, signingKey interface{}) string {
+		token := jwt.NewWithClaims(jwt.SigningMethodRS256, claims)
+		token.Header["kid"] = "key"
+		signed, err := token.SignedString(signingKey)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return signed
+	}
+	otherKey, err := rsa.GenerateKey(rand.Reader, 2048)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var userID interface{}
+	handler := v.Middleware(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		userID = log.FieldsFromContext(r.Context())[log.UserID]
+	}))
+	tt := []struct {
+		authorization string
+		status        int
+		userID        interface{}
+	}{
+		{authorization: "Bearer " + sign(jwt.MapClaims{"aud": "api", "iss": issuer.URL, "sub": "user-1"}, key), status: 200, userID: "user-1"},
+		{authorization: "Bearer " + sign(jwt.MapClaims{"aud": "api", "iss": issuer.URL}, key), status: 200},
+		{authorization: "Bearer " + sign(jwt.MapClaims{"aud": "other", "iss": issuer.URL, "sub": "user-1"}, key), status: 401},
+		{authorization: "Bearer " + sign(jwt.MapClaims{"aud": "api", "iss": issuer.URL, "sub": "user-1"}, otherKey), status: 401},
+		{authorization: "Basic dXNlcjpwYXNz", status: 401},
+		{status: 401},
+	}
+	for i, tc := range tt {
+		userID = nil
+		req := httptest.NewRequest(http.MethodGet, "/", nil)
+		if tc.authorization != "" {
+			req.Header.Set("Authorization", tc.authorization)
+		}
+		rec := httptest.NewRecorder()
+		handler.ServeHTTP(rec, req)
+		if rec.Code != tc.status || userID != tc.userID {
+			t.Errorf("#%d: expected %d with user %v but got %d with %v: %s", i, tc.status, tc.userID, rec.Code, userID, rec.Body)
+		}
+	}
+}
@@ -51,39 +51,45 @@ const (
 	Password    = "password"
 )
 
+// elasticHookSettings setting string of the es hook, see LogrusLogger
+type elasticHookSettings struct {
+	Type        string   `setting:"type" enum:"es" description:"the type of the hook"`
+	Hosts       []string `setting:"host" required:"true" description:"the comma separated urls of the elasticsearch nodes"`
+	IndexPrefix string   `setting:"index-prefix" required:"true" description:"the prefix of the daily index"`
+	Sniff       bool     `setting:"sniff" description:"whether the client sniffs the cluster nodes"`
+	Mode        string   `setting:"mode" default:"sync" enum:"sync,async" description:"whether the entries are indexed synchronously"`
+	UserName    string   `setting:"username" description:"the basic auth user name"`
+	Password    string   `setting:"password" description:"the basic auth password"`
+}
+
+// NewElasticHookFromStr creates the hook from a setting string, its secret references are resolved, see util.ResolveSecrets
 func NewElasticHookFromStr(configStr string, level logrus.Level) (*ElasticHook, error) {
 	config, err := util.GetConfig(configStr)
 	if err != nil {
 		log.Panic(err)
 	}
-	host, found := config[Host]
-	if !found || host == "" {
-		log.Panic("host empty is not valid")
+	if err := util.ResolveSecrets(config); err != nil {
+		log.Panic(err)
 	}
-	indexPrefix, found := config[IndexPrefix]
-	if !found || indexPrefix == "" {
-		log.Panic("indexPrefix empty is not valid")
+	return newElasticHook(config, level)
+}
+
+// newElasticHook creates the hook from settings whose secrets are already resolved, e.g. by config.Load
+func newElasticHook(config map[string]string, level logrus.Level) (*ElasticHook, error) {
+	var settings elasticHookSettings
+	if err := util.Bind(config, &settings); err != nil {
+		log.Panic(err)
 	}
 	indexFunc := func() string {
 		dt := time.Now()
-		return fmt.Sprintf("%s-%s", indexPrefix, dt.Format("2006-01-02"))
-	}
-	sniffEnableStr, found := config[Sniff]
-	sniffEnable := false
-	if found && sniffEnableStr == "true" {
-		sniffEnable = true
+		return fmt.Sprintf("%s-%s", settings.IndexPrefix, dt.Format("2006-01-02"))
 	}
 	var clientOptionFuncs []elastic.ClientOptionFunc
-	clientOptionFuncs = append(clientOptionFuncs, elastic.SetSniff(sniffEnable))
-	clientOptionFuncs = append(clientOptionFuncs, elastic.SetURL(strings.Split(host, ",")...))
-
-	if un, f := config[UserName]; f {
-		if un != "" {
-			pass, f := config[Password]
-			if f && pass != "" {
-				clientOptionFuncs = append(clientOptionFuncs, elastic.SetBasicAuth(un, pass))
-			}
-		}
+	clientOptionFuncs = append(clientOptionFuncs, elastic.SetSniff(settings.Sniff))
+	clientOptionFuncs = append(clientOptionFuncs, elastic.SetURL(settings.Hosts...))
+
+	if settings.UserName != "" && settings.Password != "" {
+		clientOptionFuncs = append(clientOptionFuncs, elastic.SetBasicAuth(settings.UserName, settings.Password))
 	}
 
 	client, err := elastic.NewClient(clientOptionFuncs...)
@@ -91,8 +97,8 @@ func NewElasticHookFromStr(configStr string, level logrus.Level) (*ElasticHook,
 		log.Panic(err)
 	}
 
-	mode, found := config[Mode]
-	if found && mode == "async" {
+	host := strings.Join(settings.Hosts, ",")
+	if settings.Mode == "async" {
 		return NewAsyncElasticHookWithFunc(client, host, level, indexFunc)
 	}
 	return NewElasticHookWithFunc(client, host, level, indexFunc)
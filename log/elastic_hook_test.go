@@ -0,0 +1,46 @@
+package log
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+
+	"github.com/jedrp/go-core/config"
+)
+
+func TestHookSecretsResolvedOnce(t *testing.T) {
+	var (
+		mux       sync.Mutex
+		passwords = make(map[string]bool)
+	)
+	es := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if _, password, ok := r.BasicAuth(); ok {
+			mux.Lock()
+			passwords[password] = true
+			mux.Unlock()
+		}
+		w.Header().Set("Content-Type", "application/json")
+		w.Write([]byte(`{}`))
+	}))
+	t.Cleanup(es.Close)
+	t.Setenv("ES_PASSWORD", "env:NOT_SET")
+
+	c, err := config.Load(config.Defaults(map[string]config.Section{
+		config.LogSection: {"level": "info"},
+		// resolved by config.Load to a value that would be resolved again as a reference
+		config.LogSection + ".hook1": {"type": "es", "host": es.URL, "index-prefix": "logs", "username": "elastic", "password": "env:ES_PASSWORD"},
+		config.LogSection + ".hook2": {"type": "es", "host": es.URL, "index-prefix": "logs", "username": "elastic", "password": "literal:file:pw"},
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := newFromConfig(c, newLevelRegistry())
+	defer logger.Close()
+
+	mux.Lock()
+	defer mux.Unlock()
+	if !passwords["env:NOT_SET"] || !passwords["file:pw"] || len(passwords) != 2 {
+		t.Errorf("expected the passwords to be used as resolved by config.Load but got %v", passwords)
+	}
+}
@@ -0,0 +1,241 @@
+package log
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// RootLoggerName name of the level of the loggers without name
+const RootLoggerName = ""
+
+// LevelSetting is the level of a logger name, RevertAt is set when the level is temporary
+type LevelSetting struct {
+	Name     string
+	Level    logrus.Level
+	RevertAt time.Time
+}
+
+type levelOverride struct {
+	level    logrus.Level
+	revertAt time.Time
+	timer    *time.Timer
+	// level restored by the timer, nil to remove the override
+	revertTo *logrus.Level
+}
+
+// levelRegistry resolves the levels of the loggers by name, a name without level takes the level of its parent,
+// e.g. "cqs.dispatcher" takes the level of "cqs" then of the root.
+// Levels set at runtime override the configured ones.
+type levelRegistry struct {
+	mux        sync.Mutex
+	configured map[string]logrus.Level
+	overrides  map[string]*levelOverride
+	loggers    map[string][]*logrus.Logger
+	// effective levels of the names configured or set explicitly, root excluded, read without locking
+	explicit atomic.Pointer[map[string]logrus.Level]
+	// the first logger created by New watches the configuration for all of them
+	watchOnce sync.Once
+}
+
+// defaultLevels levels of DefaultLogger and of the loggers created by New
+var defaultLevels = newLevelRegistry()
+
+func newLevelRegistry() *levelRegistry {
+	r := &levelRegistry{
+		configured: map[string]logrus.Level{RootLoggerName: logrus.DebugLevel},
+		overrides:  make(map[string]*levelOverride),
+		loggers:    make(map[string][]*logrus.Logger),
+	}
+	r.apply()
+	return r
+}
+
+// SetLevel changes the level of the loggers of the name and of its children without level, RootLoggerName
+// changes all of them. When revertAfter is positive the previous level is restored after that duration.
+func SetLevel(name string, level logrus.Level, revertAfter time.Duration) {
+	defaultLevels.set(name, level, revertAfter)
+}
+
+// ResetLevel removes the level set at runtime for the name, its configured level applies again
+func ResetLevel(name string) {
+	defaultLevels.reset(name)
+}
+
+// GetLevel returns the effective level of the name
+func GetLevel(name string) logrus.Level {
+	return defaultLevels.level(name)
+}
+
+// Levels returns the configured levels and the ones set at runtime, sorted by name
+func Levels() []LevelSetting {
+	return defaultLevels.settings()
+}
+
+func (r *levelRegistry) set(name string, level logrus.Level, revertAfter time.Duration) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	o := &levelOverride{level: level}
+	if previous, found := r.overrides[name]; found {
+		if previous.timer != nil {
+			previous.timer.Stop()
+		}
+		if revertAfter > 0 {
+			// the first temporary change keeps the level to restore
+			o.revertTo = previous.revertTo
+			if previous.timer == nil {
+				l := previous.level
+				o.revertTo = &l
+			}
+		}
+	}
+	if revertAfter > 0 {
+		o.revertAt = time.Now().Add(revertAfter)
+		o.timer = time.AfterFunc(revertAfter, func() { r.revert(name, o) })
+	}
+	r.overrides[name] = o
+	r.apply()
+}
+
+func (r *levelRegistry) revert(name string, o *levelOverride) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if r.overrides[name] != o {
+		return
+	}
+	if o.revertTo != nil {
+		r.overrides[name] = &levelOverride{level: *o.revertTo}
+	} else {
+		delete(r.overrides, name)
+	}
+	r.apply()
+}
+
+func (r *levelRegistry) reset(name string) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	if o, found := r.overrides[name]; found {
+		if o.timer != nil {
+			o.timer.Stop()
+		}
+		delete(r.overrides, name)
+		r.apply()
+	}
+}
+
+// configureAll replaces the configured levels, levels must hold the level of RootLoggerName
+func (r *levelRegistry) configureAll(levels map[string]logrus.Level) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.configured = levels
+	r.apply()
+}
+
+// register makes the level of logger follow the level of the name
+func (r *levelRegistry) register(name string, logger *logrus.Logger) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	r.loggers[name] = append(r.loggers[name], logger)
+	logger.SetLevel(r.resolve(name))
+}
+
+// unregister stops applying the levels to logger
+func (r *levelRegistry) unregister(logger *logrus.Logger) {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	for name, loggers := range r.loggers {
+		for i, l := range loggers {
+			if l == logger {
+				loggers = append(loggers[:i:i], loggers[i+1:]...)
+				break
+			}
+		}
+		if len(loggers) == 0 {
+			delete(r.loggers, name)
+		} else {
+			r.loggers[name] = loggers
+		}
+	}
+}
+
+func (r *levelRegistry) level(name string) logrus.Level {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	return r.resolve(name)
+}
+
+func (r *levelRegistry) settings() []LevelSetting {
+	r.mux.Lock()
+	defer r.mux.Unlock()
+	settings := make(map[string]LevelSetting, len(r.configured)+len(r.overrides))
+	for name, level := range r.configured {
+		settings[name] = LevelSetting{Name: name, Level: level}
+	}
+	for name, o := range r.overrides {
+		settings[name] = LevelSetting{Name: name, Level: o.level, RevertAt: o.revertAt}
+	}
+	sorted := make([]LevelSetting, 0, len(settings))
+	for _, s := range settings {
+		sorted = append(sorted, s)
+	}
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].Name < sorted[j].Name })
+	return sorted
+}
+
+// resolve returns the level of the nearest name, an override winning over the configured level of the same name
+func (r *levelRegistry) resolve(name string) logrus.Level {
+	for {
+		if o, found := r.overrides[name]; found {
+			return o.level
+		}
+		if level, found := r.configured[name]; found {
+			return level
+		}
+		if name == RootLoggerName {
+			return logrus.DebugLevel
+		}
+		name = parentName(name)
+	}
+}
+
+// explicitLevel returns the level of the nearest name configured or set explicitly, the root excluded,
+// false when neither the name nor its parents have one
+func (r *levelRegistry) explicitLevel(name string) (logrus.Level, bool) {
+	explicit := *r.explicit.Load()
+	for ; name != RootLoggerName; name = parentName(name) {
+		if level, found := explicit[name]; found {
+			return level, true
+		}
+	}
+	return 0, false
+}
+
+func (r *levelRegistry) apply() {
+	explicit := make(map[string]logrus.Level, len(r.configured)+len(r.overrides))
+	for name := range r.configured {
+		explicit[name] = r.resolve(name)
+	}
+	for name := range r.overrides {
+		explicit[name] = r.resolve(name)
+	}
+	delete(explicit, RootLoggerName)
+	r.explicit.Store(&explicit)
+
+	for name, loggers := range r.loggers {
+		level := r.resolve(name)
+		for _, l := range loggers {
+			l.SetLevel(level)
+		}
+	}
+}
+
+func parentName(name string) string {
+	if i := strings.LastIndexByte(name, '.'); i >= 0 {
+		return name[:i]
+	}
+	return RootLoggerName
+}
@@ -0,0 +1,148 @@
+package log
+
+import (
+	"testing"
+	"time"
+
+	"github.com/jedrp/go-core/config"
+	"github.com/sirupsen/logrus"
+)
+
+func newTestLogger(t *testing.T, levels *levelRegistry, settings config.Section) *LogrusLogger {
+	c, err := config.Load(config.Defaults(map[string]config.Section{config.LogSection: settings}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	return newFromConfig(c, levels)
+}
+
+func TestCloseUnregisters(t *testing.T) {
+	levels := newLevelRegistry()
+	logger := newTestLogger(t, levels, config.Section{"level": "info"})
+	child := logger.Named("cqs").(*LogrusLogger)
+	kept := newTestLogger(t, levels, config.Section{"level": "info"})
+
+	logger.Close()
+	levels.set(RootLoggerName, logrus.DebugLevel, 0)
+	if logger.Level != logrus.InfoLevel || child.Level != logrus.InfoLevel {
+		t.Errorf("expected closed loggers to keep info but got %s and %s", logger.Level, child.Level)
+	}
+	if kept.Level != logrus.DebugLevel {
+		t.Errorf("expected the open logger to follow the registry but got %s", kept.Level)
+	}
+	if len(levels.loggers[RootLoggerName]) != 1 || len(levels.loggers["cqs"]) != 0 {
+		t.Errorf("expected only the open logger to stay registered but got %v", levels.loggers)
+	}
+}
+
+func TestLevelResolution(t *testing.T) {
+	levels := newLevelRegistry()
+	levels.configureAll(map[string]logrus.Level{RootLoggerName: logrus.InfoLevel, "cqs": logrus.WarnLevel})
+
+	tt := []struct {
+		name     string
+		set      map[string]logrus.Level
+		reset    []string
+		expected map[string]logrus.Level
+	}{
+		{
+			name:     "configured",
+			expected: map[string]logrus.Level{"": logrus.InfoLevel, "cqs": logrus.WarnLevel, "cqs.dispatcher": logrus.WarnLevel, "rest": logrus.InfoLevel},
+		},
+		{
+			name:     "override parent",
+			set:      map[string]logrus.Level{"cqs": logrus.DebugLevel},
+			expected: map[string]logrus.Level{"": logrus.InfoLevel, "cqs": logrus.DebugLevel, "cqs.dispatcher": logrus.DebugLevel},
+		},
+		{
+			name:     "override child",
+			set:      map[string]logrus.Level{"cqs.dispatcher": logrus.TraceLevel},
+			expected: map[string]logrus.Level{"cqs": logrus.DebugLevel, "cqs.dispatcher": logrus.TraceLevel},
+		},
+		{
+			name:     "override root",
+			set:      map[string]logrus.Level{RootLoggerName: logrus.ErrorLevel},
+			expected: map[string]logrus.Level{"": logrus.ErrorLevel, "cqs": logrus.DebugLevel, "rest": logrus.ErrorLevel},
+		},
+		{
+			name:     "reset",
+			reset:    []string{"cqs", RootLoggerName},
+			expected: map[string]logrus.Level{"": logrus.InfoLevel, "cqs": logrus.WarnLevel, "cqs.dispatcher": logrus.TraceLevel, "rest": logrus.InfoLevel},
+		},
+	}
+	for _, tc := range tt {
+		for name, level := range tc.set {
+			levels.set(name, level, 0)
+		}
+		for _, name := range tc.reset {
+			levels.reset(name)
+		}
+		for name, level := range tc.expected {
+			if l := levels.level(name); l != level {
+				t.Errorf("%s: expected %q at %s but got %s", tc.name, name, level, l)
+			}
+		}
+	}
+}
+
+func TestRegisteredLoggersFollowLevels(t *testing.T) {
+	levels := newLevelRegistry()
+	root, child := logrus.New(), logrus.New()
+	levels.register(RootLoggerName, root)
+	levels.register("cqs.dispatcher", child)
+
+	levels.configureAll(map[string]logrus.Level{RootLoggerName: logrus.InfoLevel})
+	levels.set("cqs", logrus.TraceLevel, 0)
+	if root.Level != logrus.InfoLevel || child.Level != logrus.TraceLevel {
+		t.Errorf("expected info and trace but got %s and %s", root.Level, child.Level)
+	}
+}
+
+func waitLevel(t *testing.T, levels *levelRegistry, name string, level logrus.Level) {
+	t.Helper()
+	deadline := time.Now().Add(2 * time.Second)
+	for levels.level(name) != level {
+		if time.Now().After(deadline) {
+			t.Fatalf("expected %q to revert to %s but got %s", name, level, levels.level(name))
+		}
+		time.Sleep(5 * time.Millisecond)
+	}
+}
+
+func TestTemporaryLevel(t *testing.T) {
+	levels := newLevelRegistry()
+	levels.configureAll(map[string]logrus.Level{RootLoggerName: logrus.InfoLevel})
+
+	levels.set("cqs", logrus.DebugLevel, 20*time.Millisecond)
+	settings := levels.settings()
+	if len(settings) != 2 || settings[1].Name != "cqs" || settings[1].RevertAt.IsZero() {
+		t.Errorf("expected the temporary level to be listed with its revert time but got %v", settings)
+	}
+	if l := levels.level("cqs"); l != logrus.DebugLevel {
+		t.Errorf("expected debug but got %s", l)
+	}
+	waitLevel(t, levels, "cqs", logrus.InfoLevel)
+	if settings := levels.settings(); len(settings) != 1 {
+		t.Errorf("expected the reverted override to be removed but got %v", settings)
+	}
+
+	// a temporary change over a temporary change restores the level before the first one
+	levels.set("cqs", logrus.WarnLevel, 0)
+	levels.set("cqs", logrus.DebugLevel, time.Hour)
+	levels.set("cqs", logrus.TraceLevel, 20*time.Millisecond)
+	if l := levels.level("cqs"); l != logrus.TraceLevel {
+		t.Errorf("expected trace but got %s", l)
+	}
+	waitLevel(t, levels, "cqs", logrus.WarnLevel)
+	if s := levels.settings()[1]; s.Level != logrus.WarnLevel || !s.RevertAt.IsZero() {
+		t.Errorf("expected the permanent warn level to be restored but got %v", s)
+	}
+
+	// a permanent change cancels the revert
+	levels.set("cqs", logrus.DebugLevel, 20*time.Millisecond)
+	levels.set("cqs", logrus.ErrorLevel, 0)
+	time.Sleep(50 * time.Millisecond)
+	if l := levels.level("cqs"); l != logrus.ErrorLevel {
+		t.Errorf("expected the permanent change to be kept but got %s", l)
+	}
+}
@@ -1,17 +1,70 @@
 package log
 
-import "context"
+import (
+	"context"
+
+	"github.com/jedrp/go-core/log/logctx"
+)
 
 const (
 	RequestIDHeaderKey     = "Request-Id"
 	CorrelationIDHeaderKey = "Correlation-Id"
-	RequestID              = "RequestId"
-	CorrelationID          = "CorrelationId"
+	RequestID              = logctx.RequestID
+	CorrelationID          = logctx.CorrelationID
+	TenantID               = logctx.TenantID
+	UserID                 = logctx.UserID
+	HandlerID              = logctx.HandlerID
+	TraceID                = logctx.TraceID
+	SpanID                 = logctx.SpanID
+	// Component field holding the name of a named logger, see Logger.Named
+	Component = "Component"
+	// Stack field holding a stack trace, e.g. of a recovered panic
+	Stack = "Stack"
 )
 
+// ContextWithFields returns a context carrying the fields added to the previous ones,
+// they are included in the entries of the *WithContext log methods
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	return logctx.ContextWithFields(ctx, fields)
+}
+
+// ContextWithRequestID returns a context carrying the request ID field
+func ContextWithRequestID(ctx context.Context, requestID string) context.Context {
+	return ContextWithFields(ctx, map[string]interface{}{RequestID: requestID})
+}
+
+// ContextWithCorrelationID returns a context carrying the correlation ID field
+func ContextWithCorrelationID(ctx context.Context, correlationID string) context.Context {
+	return ContextWithFields(ctx, map[string]interface{}{CorrelationID: correlationID})
+}
+
+// FieldsFromContext returns a copy of the fields of ctx,
+// the IDs stored under the plain string keys by older code are included
+func FieldsFromContext(ctx context.Context) map[string]interface{} {
+	return logctx.FieldsFromContext(ctx)
+}
+
+// RequestIDFromContext returns the request ID field of ctx, empty if none
+func RequestIDFromContext(ctx context.Context) string {
+	return logctx.StringField(ctx, RequestID)
+}
+
+// CorrelationIDFromContext returns the correlation ID field of ctx, empty if none
+func CorrelationIDFromContext(ctx context.Context) string {
+	return logctx.StringField(ctx, CorrelationID)
+}
+
+// TenantIDFromContext returns the tenant ID field of ctx, empty if none
+func TenantIDFromContext(ctx context.Context) string {
+	return logctx.StringField(ctx, TenantID)
+}
+
 func CreateRequestLogEntryFromContext(ctx context.Context, log Logger) LogEntry {
-	return log.WithFields(map[string]interface{}{
-		CorrelationID: ctx.Value(CorrelationID),
-		RequestID:     ctx.Value(RequestID),
-	})
+	fields := FieldsFromContext(ctx)
+	for _, key := range []string{CorrelationID, RequestID} {
+		if _, found := fields[key]; !found {
+			fields[key] = nil
+		}
+	}
+	return log.WithFields(fields)
 }
@@ -0,0 +1,75 @@
+// Package logctx holds the log fields carried by a context. It has no dependency, so packages reading
+// the request fields, e.g. result, do not initialize the loggers of the log package.
+package logctx
+
+import "context"
+
+// Names of the fields set by the go-core packages, see the log package
+const (
+	RequestID     = "RequestId"
+	CorrelationID = "CorrelationId"
+	TenantID      = "TenantId"
+	UserID        = "UserId"
+	HandlerID     = "HandlerId"
+	TraceID       = "TraceId"
+	SpanID        = "SpanId"
+)
+
+// legacyContextKeys fields also stored under their name as plain string context keys, read by older code
+var legacyContextKeys = []string{CorrelationID, RequestID, TenantID}
+
+type fieldsContextKey struct{}
+
+// ContextWithFields returns a context carrying the fields added to the previous ones
+func ContextWithFields(ctx context.Context, fields map[string]interface{}) context.Context {
+	merged := make(map[string]interface{}, len(fields))
+	if parent, ok := ctx.Value(fieldsContextKey{}).(map[string]interface{}); ok {
+		for k, v := range parent {
+			merged[k] = v
+		}
+	}
+	for k, v := range fields {
+		merged[k] = v
+	}
+	ctx = context.WithValue(ctx, fieldsContextKey{}, merged)
+	for _, key := range legacyContextKeys {
+		if v, found := fields[key]; found {
+			ctx = context.WithValue(ctx, key, v)
+		}
+	}
+	return ctx
+}
+
+// FieldsFromContext returns a copy of the fields of ctx,
+// the IDs stored under the plain string keys by older code are included
+func FieldsFromContext(ctx context.Context) map[string]interface{} {
+	fields := make(map[string]interface{})
+	if ctx == nil {
+		return fields
+	}
+	for _, key := range legacyContextKeys {
+		if v := ctx.Value(key); v != nil {
+			fields[key] = v
+		}
+	}
+	if values, ok := ctx.Value(fieldsContextKey{}).(map[string]interface{}); ok {
+		for k, v := range values {
+			fields[k] = v
+		}
+	}
+	return fields
+}
+
+// StringField returns the string field of ctx, empty if none
+func StringField(ctx context.Context, key string) string {
+	if ctx == nil {
+		return ""
+	}
+	if values, ok := ctx.Value(fieldsContextKey{}).(map[string]interface{}); ok {
+		if v, ok := values[key].(string); ok {
+			return v
+		}
+	}
+	v, _ := ctx.Value(key).(string)
+	return v
+}
@@ -23,6 +23,9 @@ type Logger interface {
 	Fatalf(format string, args ...interface{})
 	Panicf(format string, args ...interface{})
 	WithFields(map[string]interface{}) LogEntry
+	// Named returns the child logger of the name, adding the Component field, whose level is set
+	// by level.<name> of the "log" section or by SetLevel. Names of nested children are joined with ".".
+	Named(name string) Logger
 
 	TraceWithContext(ctx context.Context, args ...interface{})
 	DebugWithContext(ctx context.Context, args ...interface{})
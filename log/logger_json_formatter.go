@@ -0,0 +1,155 @@
+package log
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"runtime"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// JSONFieldNames names of the fixed fields written by LoggerJSONFormatter, empty names take the default
+type JSONFieldNames struct {
+	Timestamp     string
+	Level         string
+	Message       string
+	RequestID     string
+	CorrelationID string
+	Caller        string
+	Error         string
+	Stack         string
+}
+
+// DefaultJSONFieldNames names of the fixed fields of LoggerJSONFormatter
+var DefaultJSONFieldNames = JSONFieldNames{
+	Timestamp:     "timestamp",
+	Level:         "level",
+	Message:       "message",
+	RequestID:     "requestId",
+	CorrelationID: "correlationId",
+	Caller:        "caller",
+	Error:         "error",
+	Stack:         "stack",
+}
+
+// LoggerJSONFormatter formats logs into one JSON object per line, entry fields are written next to the fixed fields
+// and prefixed with "fields." when their name clashes with a fixed field
+type LoggerJSONFormatter struct {
+	// TimestampFormat to use, time.RFC3339Nano by default
+	TimestampFormat string
+
+	// Disable timestamp logging
+	DisableTimestamp bool
+
+	// FieldNames overrides the names of the fixed fields
+	FieldNames JSONFieldNames
+
+	// CallerPrettyfier can be set by the user to modify the content
+	// of the caller field when ReportCaller is activated, written as "function file"
+	CallerPrettyfier func(*runtime.Frame) (function string, file string)
+}
+
+// Format renders a single log entry
+func (f *LoggerJSONFormatter) Format(entry *logrus.Entry) ([]byte, error) {
+	names := f.fieldNames()
+	data := make(map[string]interface{}, len(entry.Data)+6)
+	fixed := make(map[string]bool, 8)
+	set := func(name string, value interface{}) {
+		data[name] = value
+		fixed[name] = true
+	}
+
+	if !f.DisableTimestamp {
+		timestampFormat := f.TimestampFormat
+		if timestampFormat == "" {
+			timestampFormat = time.RFC3339Nano
+		}
+		set(names.Timestamp, entry.Time.Format(timestampFormat))
+	}
+	set(names.Level, entry.Level.String())
+	set(names.Message, entry.Message)
+	if entry.HasCaller() {
+		set(names.Caller, f.caller(entry.Caller))
+	}
+
+	// the names of the renamed fields are reserved too, whether the entry has them or not
+	renamed := map[string]string{
+		RequestID:       names.RequestID,
+		CorrelationID:   names.CorrelationID,
+		logrus.ErrorKey: names.Error,
+		Stack:           names.Stack,
+	}
+	for _, name := range renamed {
+		fixed[name] = true
+	}
+
+	for k, v := range entry.Data {
+		name, found := renamed[k]
+		if !found {
+			name = k
+			if fixed[k] {
+				name = "fields." + k
+			}
+		}
+		if err, ok := v.(error); ok {
+			// errors usually have no exported field and would be written as {}
+			v = err.Error()
+		}
+		if v == nil {
+			continue
+		}
+		data[name] = v
+	}
+
+	var b *bytes.Buffer
+	if entry.Buffer != nil {
+		b = entry.Buffer
+	} else {
+		b = &bytes.Buffer{}
+	}
+	encoder := json.NewEncoder(b)
+	encoder.SetEscapeHTML(false)
+	if err := encoder.Encode(data); err != nil {
+		return nil, fmt.Errorf("failed to marshal fields to JSON, %w", err)
+	}
+	return b.Bytes(), nil
+}
+
+func (f *LoggerJSONFormatter) fieldNames() JSONFieldNames {
+	names := f.FieldNames
+	defaults := DefaultJSONFieldNames
+	for _, n := range []struct {
+		name *string
+		def  string
+	}{
+		{&names.Timestamp, defaults.Timestamp},
+		{&names.Level, defaults.Level},
+		{&names.Message, defaults.Message},
+		{&names.RequestID, defaults.RequestID},
+		{&names.CorrelationID, defaults.CorrelationID},
+		{&names.Caller, defaults.Caller},
+		{&names.Error, defaults.Error},
+		{&names.Stack, defaults.Stack},
+	} {
+		if *n.name == "" {
+			*n.name = n.def
+		}
+	}
+	return names
+}
+
+func (f *LoggerJSONFormatter) caller(frame *runtime.Frame) string {
+	if f.CallerPrettyfier != nil {
+		function, file := f.CallerPrettyfier(frame)
+		if function == "" {
+			return file
+		}
+		if file == "" {
+			return function
+		}
+		return function + " " + file
+	}
+	return fmt.Sprintf("%s %s:%d", frame.Function, frame.File, frame.Line)
+}
@@ -0,0 +1,102 @@
+package log
+
+import (
+	"encoding/json"
+	"errors"
+	"runtime"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestJSONFormatter(t *testing.T) {
+	entryTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	caller := &runtime.Frame{Function: "pkg.Func", File: "/src/pkg/file.go", Line: 42}
+	tt := []struct {
+		formatter *LoggerJSONFormatter
+		data      logrus.Fields
+		caller    *runtime.Frame
+		expected  map[string]interface{}
+		absent    []string
+	}{
+		{
+			formatter: &LoggerJSONFormatter{},
+			data:      logrus.Fields{RequestID: "req-1", CorrelationID: "cor-1", "user": "bob"},
+			expected: map[string]interface{}{
+				"timestamp": "2020-01-02T03:04:05Z", "level": "info", "message": "hello",
+				"requestId": "req-1", "correlationId": "cor-1", "user": "bob",
+			},
+			absent: []string{"caller"},
+		},
+		{
+			formatter: &LoggerJSONFormatter{FieldNames: JSONFieldNames{Timestamp: "@timestamp", Message: "msg", RequestID: "rid"}},
+			data:      logrus.Fields{RequestID: "req-1"},
+			expected:  map[string]interface{}{"@timestamp": "2020-01-02T03:04:05Z", "msg": "hello", "rid": "req-1", "level": "info"},
+			absent:    []string{"timestamp", "message", "requestId"},
+		},
+		{
+			formatter: &LoggerJSONFormatter{FieldNames: jsonFieldNames(map[string]string{"field.timestamp": "@t", "field.level": "severity", "field.error": "err"})},
+			data:      logrus.Fields{logrus.ErrorKey: errors.New("failed")},
+			expected:  map[string]interface{}{"@t": "2020-01-02T03:04:05Z", "severity": "info", "err": "failed"},
+			absent:    []string{"timestamp", "level", "error"},
+		},
+		{
+			formatter: &LoggerJSONFormatter{},
+			data:      logrus.Fields{"level": "custom", "message": "field", "cause": errors.New("failed"), "empty": nil},
+			expected:  map[string]interface{}{"level": "info", "message": "hello", "fields.level": "custom", "fields.message": "field", "cause": "failed"},
+			absent:    []string{"empty"},
+		},
+		{
+			formatter: &LoggerJSONFormatter{},
+			data:      logrus.Fields{RequestID: "req-1", "requestId": "field", Stack: "trace", "stack": "field"},
+			expected:  map[string]interface{}{"requestId": "req-1", "fields.requestId": "field", "stack": "trace", "fields.stack": "field"},
+		},
+		{
+			formatter: &LoggerJSONFormatter{FieldNames: JSONFieldNames{Error: "err"}},
+			data:      logrus.Fields{"err": "field"},
+			expected:  map[string]interface{}{"fields.err": "field"},
+			absent:    []string{"err"},
+		},
+		{
+			formatter: &LoggerJSONFormatter{DisableTimestamp: true, TimestampFormat: time.Kitchen},
+			caller:    caller,
+			expected:  map[string]interface{}{"caller": "pkg.Func /src/pkg/file.go:42"},
+			absent:    []string{"timestamp"},
+		},
+		{
+			formatter: &LoggerJSONFormatter{
+				TimestampFormat:  time.Kitchen,
+				CallerPrettyfier: func(f *runtime.Frame) (string, string) { return "", "file.go" },
+			},
+			caller:   caller,
+			expected: map[string]interface{}{"timestamp": "3:04AM", "caller": "file.go"},
+		},
+	}
+	for i, tc := range tt {
+		logger := logrus.New()
+		logger.ReportCaller = tc.caller != nil
+		data := tc.data
+		if data == nil {
+			data = logrus.Fields{}
+		}
+		b, err := tc.formatter.Format(&logrus.Entry{Logger: logger, Time: entryTime, Level: logrus.InfoLevel, Message: "hello", Data: data, Caller: tc.caller})
+		if err != nil {
+			t.Fatal(err)
+		}
+		var got map[string]interface{}
+		if err := json.Unmarshal(b, &got); err != nil {
+			t.Fatalf("#%d: %v in %s", i, err, b)
+		}
+		for k, v := range tc.expected {
+			if got[k] != v {
+				t.Errorf("#%d: expected %s=%v but got %v", i, k, v, got[k])
+			}
+		}
+		for _, k := range tc.absent {
+			if _, found := got[k]; found {
+				t.Errorf("#%d: expected no %s in %s", i, k, b)
+			}
+		}
+	}
+}
@@ -3,7 +3,13 @@ package log
 import (
 	"bytes"
 	"fmt"
+	"io"
+	"os"
 	"runtime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
 	"time"
 
 	"github.com/sirupsen/logrus"
@@ -35,8 +41,15 @@ var LevelMapping = map[logrus.Level]string{
 	logrus.TraceLevel: "Trace",
 }
 
+var levelTextMaxLength int
+
 func init() {
 	baseTimestamp = time.Now()
+	for _, text := range LevelMapping {
+		if len(text) > levelTextMaxLength {
+			levelTextMaxLength = len(text)
+		}
+	}
 }
 
 // TextFormatter formats logs into text
@@ -59,8 +72,13 @@ type LoggerTextFormatter struct {
 
 	// Enable logging the full timestamp when a TTY is attached instead of just
 	// the time passed since beginning of execution.
+	// The full timestamp is logged unless ElapsedTimestamp is set.
 	FullTimestamp bool
 
+	// ElapsedTimestamp logs the seconds passed since the beginning of execution instead
+	// of the full timestamp when the output is colored, unless FullTimestamp is set.
+	ElapsedTimestamp bool
+
 	// TimestampFormat to use for display when a full timestamp is printed
 	TimestampFormat string
 
@@ -85,6 +103,8 @@ type LoggerTextFormatter struct {
 	// Whether the logger's out is to a terminal
 	isTerminal bool
 
+	terminalInitOnce sync.Once
+
 	// FieldMap allows users to customize the names of keys for default fields.
 	// As an example:
 	// formatter := &TextFormatter{
@@ -101,24 +121,66 @@ type LoggerTextFormatter struct {
 	CallerPrettyfier func(*runtime.Frame) (function string, file string)
 }
 
-// Format renders a single log entry
+// Format renders a single log entry as logfmt: the fixed keys, the message, the caller then the other fields
 func (f *LoggerTextFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	data := make(logrus.Fields)
 	for k, v := range entry.Data {
 		data[k] = v
 	}
 
-	fixedKeys := make([]string, 0, 4)
+	f.terminalInitOnce.Do(func() { f.init(entry) })
+	colored := f.isColored()
+
+	timeKey := f.fieldName(logrus.FieldKeyTime, FieldKeyTime)
+	levelKey := f.fieldName(logrus.FieldKeyLevel, FieldKeyLevel)
+	msgKey := f.fieldName(logrus.FieldKeyMsg, FieldKeyMsg)
+	funcKey := f.fieldName(logrus.FieldKeyFunc, logrus.FieldKeyFunc)
+	fileKey := f.fieldName(logrus.FieldKeyFile, logrus.FieldKeyFile)
+
+	fixedKeys := make([]string, 0, 8)
 	if !f.DisableTimestamp {
-		fixedKeys = append(fixedKeys, FieldKeyTime)
+		fixedKeys = append(fixedKeys, timeKey)
 	}
-	fixedKeys = append(fixedKeys, FieldKeyLevel)
+	fixedKeys = append(fixedKeys, levelKey)
 
 	fixedKeys = append(fixedKeys, CorrelationID)
 	fixedKeys = append(fixedKeys, RequestID)
+	if _, ok := data[TenantID]; ok {
+		fixedKeys = append(fixedKeys, TenantID)
+	}
 
 	if entry.Message != "" {
-		fixedKeys = append(fixedKeys, FieldKeyMsg)
+		fixedKeys = append(fixedKeys, msgKey)
+	}
+
+	var function, file string
+	if entry.HasCaller() {
+		if f.CallerPrettyfier != nil {
+			function, file = f.CallerPrettyfier(entry.Caller)
+		} else {
+			function = entry.Caller.Function
+			file = fmt.Sprintf("%s:%d", entry.Caller.File, entry.Caller.Line)
+		}
+		if function != "" {
+			fixedKeys = append(fixedKeys, funcKey)
+		}
+		if file != "" {
+			fixedKeys = append(fixedKeys, fileKey)
+		}
+	}
+
+	fields := make([]string, 0, len(data))
+	for k := range data {
+		if k != CorrelationID && k != RequestID && k != TenantID {
+			fields = append(fields, k)
+		}
+	}
+	if !f.DisableSorting {
+		if f.SortingFunc != nil {
+			f.SortingFunc(fields)
+		} else {
+			sort.Strings(fields)
+		}
 	}
 
 	var b *bytes.Buffer
@@ -132,38 +194,157 @@ func (f *LoggerTextFormatter) Format(entry *logrus.Entry) ([]byte, error) {
 	if timestampFormat == "" {
 		timestampFormat = defaultTimestampFormat
 	}
+	levelColor := 0
+	if colored {
+		levelColor = f.levelColor(entry.Level)
+	}
 	for _, key := range fixedKeys {
 		var value interface{}
 		switch {
-		case key == FieldKeyTime:
-			value = entry.Time.Format(timestampFormat)
-		case key == FieldKeyLevel:
-			value = LevelMapping[entry.Level]
-		case key == FieldKeyMsg:
+		case key == timeKey:
+			if colored && f.ElapsedTimestamp && !f.FullTimestamp {
+				value = fmt.Sprintf("%04d", int(entry.Time.Sub(baseTimestamp)/time.Second))
+			} else {
+				value = entry.Time.Format(timestampFormat)
+			}
+		case key == levelKey:
+			value = f.levelText(entry.Level, colored)
+		case key == msgKey:
 			value = entry.Message
+		case key == funcKey && entry.HasCaller():
+			value = function
+		case key == fileKey && entry.HasCaller():
+			value = file
 		default:
 			value = data[key]
 		}
-		f.appendKeyValue(b, key, value)
+		f.appendKeyValue(b, key, value, levelColor)
+	}
+	for _, key := range fields {
+		name := key
+		// entry fields cannot override the fixed keys
+		for _, fixed := range fixedKeys {
+			if key == fixed {
+				name = "fields." + key
+				break
+			}
+		}
+		f.appendKeyValue(b, name, data[key], levelColor)
 	}
 
 	b.WriteByte('\n')
 	return b.Bytes(), nil
 }
 
-func (f *LoggerTextFormatter) appendKeyValue(b *bytes.Buffer, key string, value interface{}) {
+func (f *LoggerTextFormatter) init(entry *logrus.Entry) {
+	if entry.Logger != nil {
+		f.isTerminal = checkIfTerminal(entry.Logger.Out)
+	}
+}
+
+func checkIfTerminal(w io.Writer) bool {
+	if sw, ok := w.(*syncWriter); ok {
+		w = sw.w
+	}
+	file, ok := w.(*os.File)
+	if !ok {
+		return false
+	}
+	info, err := file.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+func (f *LoggerTextFormatter) isColored() bool {
+	isColored := f.ForceColors || (f.isTerminal && runtime.GOOS != "windows")
+	if f.EnvironmentOverrideColors {
+		if force, ok := os.LookupEnv("CLICOLOR_FORCE"); ok && force != "0" {
+			isColored = true
+		} else if ok && force == "0" {
+			isColored = false
+		} else if os.Getenv("CLICOLOR") == "0" {
+			isColored = false
+		}
+	}
+	return isColored && !f.DisableColors
+}
+
+// fieldName returns the name given by FieldMap to the logrus key
+func (f *LoggerTextFormatter) fieldName(logrusKey string, defaultName string) string {
+	for k, name := range f.FieldMap {
+		if string(k) == logrusKey {
+			return name
+		}
+	}
+	return defaultName
+}
+
+func (f *LoggerTextFormatter) levelColor(level logrus.Level) int {
+	switch level {
+	case logrus.DebugLevel, logrus.TraceLevel:
+		return gray
+	case logrus.WarnLevel:
+		return yellow
+	case logrus.ErrorLevel, logrus.FatalLevel, logrus.PanicLevel:
+		return red
+	default:
+		return blue
+	}
+}
+
+func (f *LoggerTextFormatter) levelText(level logrus.Level, colored bool) string {
+	text := LevelMapping[level]
+	if !colored {
+		return text
+	}
+	text = strings.ToUpper(text)
+	switch {
+	case f.PadLevelText:
+		text += strings.Repeat(" ", levelTextMaxLength-len(text))
+	case !f.DisableLevelTruncation && len(text) > 4:
+		text = text[0:4]
+	}
+	return text
+}
+
+func (f *LoggerTextFormatter) appendKeyValue(b *bytes.Buffer, key string, value interface{}, color int) {
 	if b.Len() > 0 {
 		b.WriteByte(' ')
 	}
-	b.WriteString(key)
+	if color > 0 {
+		fmt.Fprintf(b, "\x1b[%dm%s\x1b[0m", color, key)
+	} else {
+		b.WriteString(key)
+	}
 	b.WriteByte('=')
 	f.appendValue(b, value)
 }
 
 func (f *LoggerTextFormatter) appendValue(b *bytes.Buffer, value interface{}) {
 	stringVal, ok := value.(string)
-	if !ok {
+	if !ok && value != nil {
 		stringVal = fmt.Sprint(value)
 	}
-	b.WriteString(stringVal)
+	if !f.needsQuoting(stringVal) {
+		b.WriteString(stringVal)
+	} else {
+		b.WriteString(strconv.Quote(stringVal))
+	}
+}
+
+func (f *LoggerTextFormatter) needsQuoting(text string) bool {
+	if f.ForceQuote {
+		return true
+	}
+	if len(text) == 0 {
+		return f.QuoteEmptyFields
+	}
+	for _, ch := range text {
+		if !((ch >= 'a' && ch <= 'z') ||
+			(ch >= 'A' && ch <= 'Z') ||
+			(ch >= '0' && ch <= '9') ||
+			ch == '-' || ch == '.' || ch == '_' || ch == '/' || ch == '@' || ch == '^' || ch == '+' || ch == ':') {
+			return true
+		}
+	}
+	return false
 }
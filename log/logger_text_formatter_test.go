@@ -0,0 +1,121 @@
+package log
+
+import (
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestTextFormatterTimestamp(t *testing.T) {
+	entryTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	tt := []struct {
+		formatter *LoggerTextFormatter
+		at        time.Time
+		expected  string
+	}{
+		{formatter: &LoggerTextFormatter{ForceColors: true, TimestampFormat: time.RFC3339}, expected: "2020-01-02T03:04:05Z"},
+		{formatter: &LoggerTextFormatter{ForceColors: true, ElapsedTimestamp: true, FullTimestamp: true, TimestampFormat: time.RFC3339}, expected: "2020-01-02T03:04:05Z"},
+		{formatter: &LoggerTextFormatter{ForceColors: true, ElapsedTimestamp: true}, at: baseTimestamp.Add(42 * time.Second), expected: "=0042 "},
+		{formatter: &LoggerTextFormatter{ElapsedTimestamp: true, TimestampFormat: time.RFC3339}, expected: "2020-01-02T03:04:05Z"},
+	}
+	for i, tc := range tt {
+		at := entryTime
+		if !tc.at.IsZero() {
+			at = tc.at
+		}
+		b, err := tc.formatter.Format(&logrus.Entry{Logger: logrus.New(), Time: at, Level: logrus.InfoLevel, Data: logrus.Fields{}})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), tc.expected) {
+			t.Errorf("#%d: expected %q in %q", i, tc.expected, b)
+		}
+	}
+}
+
+func TestTextFormatter(t *testing.T) {
+	entryTime := time.Date(2020, 1, 2, 3, 4, 5, 0, time.UTC)
+	caller := &runtime.Frame{Function: "pkg.Func", File: "/src/pkg/file.go", Line: 42}
+	tt := []struct {
+		formatter *LoggerTextFormatter
+		level     logrus.Level
+		data      logrus.Fields
+		caller    *runtime.Frame
+		expected  string
+	}{
+		{
+			formatter: &LoggerTextFormatter{DisableTimestamp: true},
+			data:      logrus.Fields{RequestID: "req-1", "b": 2, "a": "x y", "empty": ""},
+			expected:  `Level=Information CorrelationId= RequestId=req-1 Message=hello a="x y" b=2 empty=`,
+		},
+		{
+			formatter: &LoggerTextFormatter{DisableTimestamp: true, QuoteEmptyFields: true},
+			data:      logrus.Fields{"empty": "", "quote": `say "hi"`},
+			expected:  `Level=Information CorrelationId="" RequestId="" Message=hello empty="" quote="say \"hi\""`,
+		},
+		{
+			formatter: &LoggerTextFormatter{DisableTimestamp: true, ForceQuote: true},
+			data:      logrus.Fields{"a": 1},
+			expected:  `Level="Information" CorrelationId="" RequestId="" Message="hello" a="1"`,
+		},
+		{
+			formatter: &LoggerTextFormatter{DisableTimestamp: true, ForceColors: true},
+			level:     logrus.WarnLevel,
+			data:      logrus.Fields{"a": 1},
+			expected:  "\x1b[33mLevel\x1b[0m=WARN \x1b[33mCorrelationId\x1b[0m= \x1b[33mRequestId\x1b[0m= \x1b[33mMessage\x1b[0m=hello \x1b[33ma\x1b[0m=1",
+		},
+		{
+			formatter: &LoggerTextFormatter{DisableTimestamp: true, ForceColors: true, PadLevelText: true},
+			level:     logrus.ErrorLevel,
+			expected:  "\x1b[31mLevel\x1b[0m=\"ERROR      \"",
+		},
+		{
+			formatter: &LoggerTextFormatter{DisableTimestamp: true, ForceColors: true, DisableColors: true},
+			level:     logrus.ErrorLevel,
+			expected:  "Level=Error CorrelationId= RequestId= Message=hello",
+		},
+		{
+			formatter: &LoggerTextFormatter{DisableTimestamp: true},
+			caller:    caller,
+			expected:  "Message=hello func=pkg.Func file=/src/pkg/file.go:42",
+		},
+		{
+			formatter: &LoggerTextFormatter{
+				DisableTimestamp: true,
+				CallerPrettyfier: func(f *runtime.Frame) (string, string) { return "", "file.go" },
+			},
+			caller:   caller,
+			expected: "Message=hello file=file.go",
+		},
+		{
+			formatter: &LoggerTextFormatter{
+				TimestampFormat: time.RFC3339,
+				FieldMap:        logrus.FieldMap{logrus.FieldKeyTime: "@timestamp", logrus.FieldKeyMsg: "msg"},
+			},
+			data:     logrus.Fields{"msg": "field", "Level": "custom"},
+			expected: "@timestamp=2020-01-02T03:04:05Z Level=Information CorrelationId= RequestId= msg=hello fields.Level=custom fields.msg=field",
+		},
+	}
+	for i, tc := range tt {
+		logger := logrus.New()
+		logger.ReportCaller = tc.caller != nil
+		level := tc.level
+		if level == 0 {
+			level = logrus.InfoLevel
+		}
+		data := tc.data
+		if data == nil {
+			data = logrus.Fields{}
+		}
+		b, err := tc.formatter.Format(&logrus.Entry{Logger: logger, Time: entryTime, Level: level, Message: "hello", Data: data, Caller: tc.caller})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(b), tc.expected) {
+			t.Errorf("#%d: expected %q in %q", i, tc.expected, b)
+		}
+	}
+}
@@ -3,11 +3,16 @@ package log
 import (
 	"context"
 	"encoding/json"
+	"fmt"
+	"io"
 	"log"
 	"os"
+	"reflect"
+	"strings"
+	"sync"
 
+	"github.com/jedrp/go-core/config"
 	"github.com/jedrp/go-core/util"
-	"github.com/jessevdk/go-flags"
 	"github.com/sirupsen/logrus"
 )
 
@@ -21,39 +26,86 @@ var (
 	DefaultLogger = New()
 )
 
+func init() {
+	config.RegisterSchema(config.LogSection, loggerSchema)
+	for _, hook := range []string{"hook1", "hook2", "hook3", "hook4"} {
+		config.RegisterSchema(config.LogSection+"."+hook, util.SchemaOf(elasticHookSettings{}))
+	}
+}
+
 // LogrusLogger logrus wrapper implementation
 // LogHook# fired when log fired
 // Elasticsearch hook format "type=[es];host=host_url;index-prefix=prefix;sniff=true|false;mode=sync|async"
 type LogrusLogger struct {
-	LogHook1 string `long:"log-hook-1" description:"the hook connection string" env:"LOG_HOOK_1" json:"hook1,omitempty"`
-	LogHook2 string `long:"log-hook-2" description:"the hook connection string" env:"LOG_HOOK_2" json:"hook2,omitempty"`
-	LogHook3 string `long:"log-hook-3" description:"the hook connection string" env:"LOG_HOOK_3" json:"hook3,omitempty"`
-	LogHook4 string `long:"log-hook-4" description:"the hook connection string" env:"LOG_HOOK_4" json:"hook4,omitempty"`
+	LogHook1 string `json:"hook1,omitempty"`
+	LogHook2 string `json:"hook2,omitempty"`
+	LogHook3 string `json:"hook3,omitempty"`
+	LogHook4 string `json:"hook4,omitempty"`
 
-	LogConfigStr   string `long:"log-config" description:"the hook connection string" env:"LOG_CONFIG" json:"logConfigStr,omitempty"`
-	logLevel       string
+	LogConfigStr string `json:"logConfigStr,omitempty"`
+	logLevel     string
+	levels       *levelRegistry
+	name         string
+	// named loggers of the tree by name, shared by its loggers
+	children       *sync.Map
 	*logrus.Logger `json:"-"`
 }
 
-// New logger
+// loggerSettings setting string of LOG_CONFIG
+type loggerSettings struct {
+	Level  string `setting:"level" default:"debug" enum:"panic,fatal,error,warn,warning,info,debug,trace" description:"the minimum level of the logged entries"`
+	Format string `setting:"format" default:"text" enum:"text,json" description:"the format of the entries written to stdout"`
+	Caller bool   `setting:"caller" description:"whether the calling function is logged"`
+}
+
+// levelPrefix prefix of the LOG_CONFIG keys setting the level of a named logger, see Logger.Named
+const levelPrefix = Loglevel + "."
+
+// fieldNamePrefix prefix of the LOG_CONFIG keys renaming the fixed fields of the json format, see JSONFieldNames
+const fieldNamePrefix = "field."
+
+var loggerSchema = append(util.SchemaOf(loggerSettings{}), util.SettingSpec{
+	Key:         levelPrefix + "*",
+	Type:        "string",
+	Enum:        []string{"panic", "fatal", "error", "warn", "warning", "info", "debug", "trace"},
+	Description: "the level of a named logger and its children, e.g. level.cqs=info",
+}, util.SettingSpec{
+	Key:         fieldNamePrefix + "*",
+	Type:        "string",
+	Description: "the name of a fixed field of the json format, e.g. field.timestamp=@timestamp",
+})
+
+// New logger configured by the "log" and "log.hook1" to "log.hook4" sections of config.Default(),
+// its level follows the reloads of config.Watch() and the changes of SetLevel.
+// It implements io.Closer, closing it stops applying the levels to it and its named loggers.
 func New() Logger {
+	logger := newFromConfig(config.Default(), defaultLevels)
+	// the levels are shared by the loggers of the registry, the first logger applies the reloads to all of them
+	defaultLevels.watchOnce.Do(func() { logger.Watch(config.Watch()) })
+	return logger
+}
+
+// NewFromConfig creates the logger from the "log" and "log.hook1" to "log.hook4" sections of c,
+// its level is not changed by SetLevel
+func NewFromConfig(c *config.Config) Logger {
+	return newFromConfig(c, newLevelRegistry())
+}
+
+func newFromConfig(c *config.Config, levels *levelRegistry) *LogrusLogger {
 	logrusLogger := &LogrusLogger{
-		logLevel: "debug",
-	}
-	parser := flags.NewParser(logrusLogger, flags.IgnoreUnknown)
-	if _, err := parser.Parse(); err != nil {
-		code := 1
-		if fe, ok := err.(*flags.Error); ok {
-			if fe.Type == flags.ErrHelp {
-				code = 0
-			}
-		}
-		os.Exit(code)
+		LogHook1:     c.Section(config.LogSection + ".hook1").String(),
+		LogHook2:     c.Section(config.LogSection + ".hook2").String(),
+		LogHook3:     c.Section(config.LogSection + ".hook3").String(),
+		LogHook4:     c.Section(config.LogSection + ".hook4").String(),
+		LogConfigStr: c.Section(config.LogSection).String(),
+		logLevel:     "debug",
+		levels:       levels,
+		children:     new(sync.Map),
 	}
 	return newWith(logrusLogger)
 }
 
-func newWith(logrusLogger *LogrusLogger) Logger {
+func newWith(logrusLogger *LogrusLogger) *LogrusLogger {
 	config, err := util.GetConfig(logrusLogger.LogConfigStr)
 	if err != nil {
 		log.Panic(err)
@@ -61,31 +113,42 @@ func newWith(logrusLogger *LogrusLogger) Logger {
 	defaultLoglevel, _ := logrus.ParseLevel(logrusLogger.logLevel)
 
 	log := &logrus.Logger{
-		Out:          os.Stdout,
+		Out:          &syncWriter{w: defaultOutput()},
 		Formatter:    new(LoggerTextFormatter),
 		Hooks:        make(logrus.LevelHooks),
 		Level:        defaultLoglevel,
 		ExitFunc:     os.Exit,
 		ReportCaller: false,
 	}
+	log.Hooks.Add(sourceHook{})
 	logrusLogger.Logger = log
+	logrusLogger.levels.register(RootLoggerName, log)
 	if config == nil {
 		return logrusLogger
 	}
 
-	if v, f := config[Loglevel]; f {
-		logrusLogger.logLevel = v
+	settings := loggerSettings{}
+	if err := util.Bind(config, &settings); err != nil {
+		log.Panic(err)
 	}
-
-	level, err := logrus.ParseLevel(logrusLogger.logLevel)
+	levels, err := configuredLevels(config)
 	if err != nil {
 		log.Panic(err)
 	}
-	log.Level = level
-	addHook(log, logrusLogger.LogHook1, level)
-	addHook(log, logrusLogger.LogHook2, level)
-	addHook(log, logrusLogger.LogHook3, level)
-	addHook(log, logrusLogger.LogHook4, level)
+	logrusLogger.logLevel = settings.Level
+	log.ReportCaller = settings.Caller
+	if settings.Format == "json" {
+		log.Formatter = &LoggerJSONFormatter{FieldNames: jsonFieldNames(config)}
+	}
+	for _, key := range util.UnknownKeys(config, loggerSchema) {
+		log.Warnf("unknown log setting %s is ignored", key)
+	}
+
+	logrusLogger.levels.configureAll(levels)
+	addHook(log, logrusLogger.LogHook1)
+	addHook(log, logrusLogger.LogHook2)
+	addHook(log, logrusLogger.LogHook3)
+	addHook(log, logrusLogger.LogHook4)
 
 	logSetting, err := json.Marshal(logrusLogger)
 	if err != nil {
@@ -95,12 +158,218 @@ func newWith(logrusLogger *LogrusLogger) Logger {
 	return logrusLogger
 }
 
-func addHook(log *logrus.Logger, hookStr string, level logrus.Level) {
+// Watch applies the levels of the "log" section to the loggers sharing the levels of the logger when w reloads it,
+// hooks require a restart
+func (logrusLogger *LogrusLogger) Watch(w *config.Watcher) {
+	w.Validate(func(c *config.Config) error {
+		if _, err := configuredLevels(c.Section(config.LogSection)); err != nil {
+			return fmt.Errorf("section %s: %w", config.LogSection, err)
+		}
+		return nil
+	})
+	w.Subscribe(func(old, new *config.Config) {
+		s := new.Section(config.LogSection)
+		if reflect.DeepEqual(old.Section(config.LogSection), s) {
+			return
+		}
+		levels, err := configuredLevels(s)
+		if err != nil {
+			return
+		}
+		logrusLogger.levels.configureAll(levels)
+		logrusLogger.Logger.Infof("log levels changed to %s", formatLevels(levels))
+	})
+}
+
+// configuredLevels returns the level of the root and the levels of the named loggers of the "log" section
+func configuredLevels(config map[string]string) (map[string]logrus.Level, error) {
+	settings := loggerSettings{}
+	if err := util.Bind(config, &settings); err != nil {
+		return nil, err
+	}
+	level, err := logrus.ParseLevel(settings.Level)
+	if err != nil {
+		return nil, err
+	}
+	levels := map[string]logrus.Level{RootLoggerName: level}
+	for key, value := range config {
+		name := strings.TrimPrefix(key, levelPrefix)
+		if name == key || name == "" {
+			continue
+		}
+		level, err := logrus.ParseLevel(value)
+		if err != nil {
+			return nil, fmt.Errorf("log setting %s: %w", key, err)
+		}
+		levels[name] = level
+	}
+	return levels, nil
+}
+
+func formatLevels(levels map[string]logrus.Level) string {
+	config := make(map[string]string, len(levels))
+	for name, level := range levels {
+		if name == RootLoggerName {
+			config[Loglevel] = level.String()
+		} else {
+			config[levelPrefix+name] = level.String()
+		}
+	}
+	return util.FormatConfig(config)
+}
+
+// Named returns the child logger of the name, writing to the output and hooks of its parent with the
+// Component field added. Its level is set by level.<name> of the "log" section or by SetLevel,
+// a name without level takes the level of its parent.
+func (logrusLogger *LogrusLogger) Named(name string) Logger {
+	if logrusLogger.name != "" {
+		name = logrusLogger.name + "." + name
+	}
+	if child, found := logrusLogger.children.Load(name); found {
+		return child.(*LogrusLogger)
+	}
+	parent := logrusLogger.Logger
+	out, ok := parent.Out.(*syncWriter)
+	if !ok {
+		// the output was replaced, the parent shares the lock of the new one with its children
+		out = &syncWriter{w: parent.Out}
+		parent.SetOutput(out)
+	}
+	child := *logrusLogger
+	child.name = name
+	child.Logger = &logrus.Logger{
+		Out:          out,
+		Formatter:    parent.Formatter,
+		Hooks:        componentHooks(parent.Hooks, name),
+		Level:        parent.Level,
+		ExitFunc:     parent.ExitFunc,
+		ReportCaller: parent.ReportCaller,
+	}
+	actual, loaded := logrusLogger.children.LoadOrStore(name, &child)
+	if !loaded {
+		logrusLogger.levels.register(name, child.Logger)
+	}
+	return actual.(*LogrusLogger)
+}
+
+// Close stops applying the levels of the registry to the logger, the named loggers of a logger created by New
+// or NewFromConfig are released with it. The loggers keep their current level. DefaultLogger must not be closed.
+func (logrusLogger *LogrusLogger) Close() error {
+	if logrusLogger.name != "" {
+		logrusLogger.children.Delete(logrusLogger.name)
+		logrusLogger.levels.unregister(logrusLogger.Logger)
+		return nil
+	}
+	logrusLogger.children.Range(func(name, child interface{}) bool {
+		logrusLogger.children.Delete(name)
+		logrusLogger.levels.unregister(child.(*LogrusLogger).Logger)
+		return true
+	})
+	logrusLogger.levels.unregister(logrusLogger.Logger)
+	return nil
+}
+
+// defaultOutput is stdout, or stderr when stdout holds the output of a configuration command
+func defaultOutput() io.Writer {
+	if config.CommandRequested() {
+		return os.Stderr
+	}
+	return os.Stdout
+}
+
+// syncWriter serializes the writes of a logger and of its named loggers to their shared output,
+// each logrus.Logger only serializes its own writes
+type syncWriter struct {
+	mux sync.Mutex
+	w   io.Writer
+}
+
+func (w *syncWriter) Write(p []byte) (int, error) {
+	w.mux.Lock()
+	defer w.mux.Unlock()
+	return w.w.Write(p)
+}
+
+// componentHook sets the Component field of the entries of a named logger
+type componentHook struct {
+	name string
+}
+
+func (h componentHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h componentHook) Fire(entry *logrus.Entry) error {
+	entry.Data[Component] = h.name
+	return nil
+}
+
+// componentHooks copies the hooks of the parent, the component hook of the name replacing the one of the parent
+// and firing first so the other hooks see the field
+func componentHooks(hooks logrus.LevelHooks, name string) logrus.LevelHooks {
+	component := componentHook{name: name}
+	copied := make(logrus.LevelHooks, len(logrus.AllLevels))
+	for _, level := range logrus.AllLevels {
+		copied[level] = append(copied[level], component)
+		for _, hook := range hooks[level] {
+			if _, ok := hook.(componentHook); !ok {
+				copied[level] = append(copied[level], hook)
+			}
+		}
+	}
+	return copied
+}
+
+func jsonFieldNames(config map[string]string) JSONFieldNames {
+	return JSONFieldNames{
+		Timestamp:     config[fieldNamePrefix+"timestamp"],
+		Level:         config[fieldNamePrefix+"level"],
+		Message:       config[fieldNamePrefix+"message"],
+		RequestID:     config[fieldNamePrefix+"requestId"],
+		CorrelationID: config[fieldNamePrefix+"correlationId"],
+		Caller:        config[fieldNamePrefix+"caller"],
+		Error:         config[fieldNamePrefix+"error"],
+		Stack:         config[fieldNamePrefix+"stack"],
+	}
+}
+
+// MarshalJSON serializes the settings of the logger with their sensitive values redacted
+func (logrusLogger *LogrusLogger) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		LogHook1     string `json:"hook1,omitempty"`
+		LogHook2     string `json:"hook2,omitempty"`
+		LogHook3     string `json:"hook3,omitempty"`
+		LogHook4     string `json:"hook4,omitempty"`
+		LogConfigStr string `json:"logConfigStr,omitempty"`
+	}{
+		LogHook1:     redactConfig(logrusLogger.LogHook1),
+		LogHook2:     redactConfig(logrusLogger.LogHook2),
+		LogHook3:     redactConfig(logrusLogger.LogHook3),
+		LogHook4:     redactConfig(logrusLogger.LogHook4),
+		LogConfigStr: redactConfig(logrusLogger.LogConfigStr),
+	})
+}
+
+func redactConfig(str string) string {
+	if str == "" {
+		return str
+	}
+	return util.RedactConfig(str)
+}
+
+// addHook adds the hook for all the levels, logrus only fires the hooks of the entries enabled by the level
+// of the logger so the hook follows the level of the registry, including the changes made at runtime
+func addHook(log *logrus.Logger, hookStr string) {
 	if hookStr != "" {
 		hookType := getHookType(hookStr)
 		switch hookType {
 		case "es":
-			hook, err := NewElasticHookFromStr(hookStr, level)
+			config, _ := util.GetConfig(hookStr)
+			for _, key := range util.UnknownKeys(config, util.SchemaOf(elasticHookSettings{})) {
+				log.Warnf("unknown es hook setting %s is ignored", key)
+			}
+			// the secrets of the hook sections are resolved by config.Load
+			hook, err := newElasticHook(config, logrus.TraceLevel)
 			if err != nil {
 				log.Panic(err)
 			}
@@ -0,0 +1,159 @@
+package log
+
+import (
+	"bytes"
+	"io"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+
+	"github.com/jedrp/go-core/config"
+	"github.com/jedrp/go-core/util"
+	"github.com/sirupsen/logrus"
+)
+
+func TestNamedLoggersShareOutputLock(t *testing.T) {
+	logger := newTestLogger(t, newLevelRegistry(), config.Section{"level": "info"})
+	// bytes.Buffer is not safe for concurrent writes
+	var buf bytes.Buffer
+	logger.SetOutput(&buf)
+	children := []Logger{logger, logger.Named("a"), logger.Named("b"), logger.Named("a").Named("c")}
+
+	var wg sync.WaitGroup
+	for _, l := range children {
+		wg.Add(1)
+		go func(l Logger) {
+			defer wg.Done()
+			for i := 0; i < 200; i++ {
+				l.Info("concurrent")
+			}
+		}(l)
+	}
+	wg.Wait()
+	lines := strings.Split(strings.TrimSuffix(buf.String(), "\n"), "\n")
+	if len(lines) != 800 {
+		t.Fatalf("expected 800 lines but got %d", len(lines))
+	}
+	for _, line := range lines {
+		if !strings.HasSuffix(line, "Message=concurrent") && !strings.Contains(line, "Message=concurrent Component=") {
+			t.Fatalf("unexpected interleaved line %q", line)
+		}
+	}
+}
+
+func TestConfiguredLevels(t *testing.T) {
+	tt := []struct {
+		config   string
+		expected map[string]logrus.Level
+		invalid  bool
+	}{
+		{config: "", expected: map[string]logrus.Level{RootLoggerName: logrus.DebugLevel}},
+		{config: "level=Info;level.cqs=debug", expected: map[string]logrus.Level{RootLoggerName: logrus.InfoLevel, "cqs": logrus.DebugLevel}},
+		{config: "level=warn;level.cqs.dispatcher=TRACE;level.rest=error;format=json", expected: map[string]logrus.Level{
+			RootLoggerName: logrus.WarnLevel, "cqs.dispatcher": logrus.TraceLevel, "rest": logrus.ErrorLevel,
+		}},
+		{config: "level.=debug", expected: map[string]logrus.Level{RootLoggerName: logrus.DebugLevel}},
+		{config: "level=verbose", invalid: true},
+		{config: "level.cqs=verbose", invalid: true},
+	}
+	for _, tc := range tt {
+		c, err := util.GetConfig(tc.config)
+		if err != nil {
+			t.Fatal(err)
+		}
+		levels, err := configuredLevels(c)
+		if tc.invalid {
+			if err == nil {
+				t.Errorf("%q: expected an error but got %v", tc.config, levels)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("%q: %v", tc.config, err)
+			continue
+		}
+		if !reflect.DeepEqual(levels, tc.expected) {
+			t.Errorf("%q: expected %v but got %v", tc.config, tc.expected, levels)
+		}
+	}
+}
+
+func TestNamedLevels(t *testing.T) {
+	levels := newLevelRegistry()
+	logger := newTestLogger(t, levels, config.Section{"level": "info", "level.cqs": "debug"})
+	cqs := logger.Named("cqs").(*LogrusLogger)
+	dispatcher := cqs.Named("dispatcher").(*LogrusLogger)
+	rest := logger.Named("rest").(*LogrusLogger)
+
+	check := func(step string, expected map[*LogrusLogger]logrus.Level) {
+		for l, level := range expected {
+			if l.Level != level {
+				t.Errorf("%s: expected %q at %s but got %s", step, l.name, level, l.Level)
+			}
+		}
+	}
+	check("configured", map[*LogrusLogger]logrus.Level{
+		logger: logrus.InfoLevel, cqs: logrus.DebugLevel, dispatcher: logrus.DebugLevel, rest: logrus.InfoLevel,
+	})
+
+	levels.set("cqs.dispatcher", logrus.TraceLevel, 0)
+	check("child set", map[*LogrusLogger]logrus.Level{
+		logger: logrus.InfoLevel, cqs: logrus.DebugLevel, dispatcher: logrus.TraceLevel, rest: logrus.InfoLevel,
+	})
+
+	levels.set(RootLoggerName, logrus.ErrorLevel, 0)
+	check("root set", map[*LogrusLogger]logrus.Level{
+		logger: logrus.ErrorLevel, cqs: logrus.DebugLevel, dispatcher: logrus.TraceLevel, rest: logrus.ErrorLevel,
+	})
+
+	if logger.Named("cqs") != cqs || cqs.Named("dispatcher") != dispatcher {
+		t.Error("expected Named to return the existing logger of the name")
+	}
+}
+
+// recordingHook keeps the message and a copy of the fields of the entries it is fired with
+type recordingHook struct {
+	entries *[]logrus.Entry
+}
+
+func (h recordingHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (h recordingHook) Fire(entry *logrus.Entry) error {
+	data := make(logrus.Fields, len(entry.Data))
+	for k, v := range entry.Data {
+		data[k] = v
+	}
+	*h.entries = append(*h.entries, logrus.Entry{Message: entry.Message, Data: data})
+	return nil
+}
+
+func TestComponentHook(t *testing.T) {
+	logger := newTestLogger(t, newLevelRegistry(), config.Section{"level": "info"})
+	logger.SetOutput(io.Discard)
+	var entries []logrus.Entry
+	logger.Logger.AddHook(recordingHook{&entries})
+
+	logger.Info("root")
+	logger.Named("cqs").Info("cqs")
+	logger.Named("cqs").Named("dispatcher").WithFields(map[string]interface{}{Component: "field"}).Info("dispatcher")
+
+	expected := []struct {
+		msg       string
+		component interface{}
+	}{
+		{msg: "root", component: nil},
+		{msg: "cqs", component: "cqs"},
+		{msg: "dispatcher", component: "cqs.dispatcher"},
+	}
+	if len(entries) != len(expected) {
+		t.Fatalf("expected %d entries but got %d", len(expected), len(entries))
+	}
+	for i, e := range expected {
+		if entries[i].Message != e.msg || entries[i].Data[Component] != e.component {
+			t.Errorf("#%d: expected %q with component %v but got %q with %v", i, e.msg, e.component, entries[i].Message, entries[i].Data[Component])
+		}
+	}
+}
@@ -0,0 +1,175 @@
+package log
+
+import (
+	"context"
+	"log/slog"
+	"runtime"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SlogHandler is a slog.Handler writing the records to a Logger, so they reach its formatter and hooks.
+// The fields of the context are added to the record fields, see ContextWithFields.
+type SlogHandler struct {
+	logger Logger
+	fields map[string]interface{}
+	group  string
+}
+
+// NewSlogHandler returns a handler writing to logger, e.g. slog.SetDefault(slog.New(log.NewSlogHandler(log.DefaultLogger)))
+func NewSlogHandler(logger Logger) *SlogHandler {
+	return &SlogHandler{logger: logger}
+}
+
+func (h *SlogHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return h.logger.IsLevelEnabled(logrusLevel(level))
+}
+
+func (h *SlogHandler) Handle(ctx context.Context, record slog.Record) error {
+	fields := make(map[string]interface{}, len(h.fields)+record.NumAttrs()+3)
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	record.Attrs(func(attr slog.Attr) bool {
+		addAttr(fields, h.group, attr)
+		return true
+	})
+	for k, v := range FieldsFromContext(ctx) {
+		fields[k] = v
+	}
+
+	level := logrusLevel(record.Level)
+	if l, ok := h.logger.(*LogrusLogger); ok {
+		// a handler does not panic, panic records are logged as fatal ones which Log does not exit on
+		if level == logrus.PanicLevel {
+			level = logrus.FatalLevel
+		}
+		// keeps the time of the record, its source is set as caller by sourceHook
+		l.Logger.WithFields(fields).WithTime(record.Time).
+			WithContext(context.WithValue(ctx, sourceContextKey{}, record.PC)).
+			Log(level, record.Message)
+		return nil
+	}
+	entry := h.logger.WithFields(fields)
+	switch level {
+	case logrus.TraceLevel:
+		entry.Trace(record.Message)
+	case logrus.DebugLevel:
+		entry.Debug(record.Message)
+	case logrus.InfoLevel:
+		entry.Info(record.Message)
+	case logrus.WarnLevel:
+		entry.Warn(record.Message)
+	default:
+		entry.Error(record.Message)
+	}
+	return nil
+}
+
+func (h *SlogHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	fields := make(map[string]interface{}, len(h.fields)+len(attrs))
+	for k, v := range h.fields {
+		fields[k] = v
+	}
+	for _, attr := range attrs {
+		addAttr(fields, h.group, attr)
+	}
+	return &SlogHandler{logger: h.logger, fields: fields, group: h.group}
+}
+
+func (h *SlogHandler) WithGroup(name string) slog.Handler {
+	if name == "" {
+		return h
+	}
+	return &SlogHandler{logger: h.logger, fields: h.fields, group: joinGroup(h.group, name)}
+}
+
+type sourceContextKey struct{}
+
+// sourceHook replaces the caller found by logrus, the SlogHandler, with the source of the slog record
+type sourceHook struct{}
+
+func (sourceHook) Levels() []logrus.Level {
+	return logrus.AllLevels
+}
+
+func (sourceHook) Fire(entry *logrus.Entry) error {
+	if entry.Caller == nil || entry.Context == nil {
+		return nil
+	}
+	if pc, ok := entry.Context.Value(sourceContextKey{}).(uintptr); ok && pc != 0 {
+		frame, _ := runtime.CallersFrames([]uintptr{pc}).Next()
+		entry.Caller = &frame
+	}
+	return nil
+}
+
+// addAttr adds the attribute as a field, the keys of the attributes of groups are prefixed with "group."
+func addAttr(fields map[string]interface{}, group string, attr slog.Attr) {
+	value := attr.Value.Resolve()
+	if value.Kind() == slog.KindGroup {
+		for _, a := range value.Group() {
+			addAttr(fields, joinGroup(group, attr.Key), a)
+		}
+		return
+	}
+	if attr.Key == "" {
+		return
+	}
+	fields[joinGroup(group, attr.Key)] = value.Any()
+}
+
+func joinGroup(group, key string) string {
+	if group == "" {
+		return key
+	}
+	if key == "" {
+		return group
+	}
+	return group + "." + key
+}
+
+// Levels of the slog records of the logrus levels without slog equivalent
+const (
+	SlogLevelTrace = slog.LevelDebug - 4
+	SlogLevelFatal = slog.LevelError + 4
+	SlogLevelPanic = slog.LevelError + 8
+)
+
+func logrusLevel(level slog.Level) logrus.Level {
+	switch {
+	case level < slog.LevelDebug:
+		return logrus.TraceLevel
+	case level < slog.LevelInfo:
+		return logrus.DebugLevel
+	case level < slog.LevelWarn:
+		return logrus.InfoLevel
+	case level < slog.LevelError:
+		return logrus.WarnLevel
+	case level < SlogLevelFatal:
+		return logrus.ErrorLevel
+	case level < SlogLevelPanic:
+		return logrus.FatalLevel
+	default:
+		return logrus.PanicLevel
+	}
+}
+
+func slogLevel(level logrus.Level) slog.Level {
+	switch level {
+	case logrus.TraceLevel:
+		return SlogLevelTrace
+	case logrus.DebugLevel:
+		return slog.LevelDebug
+	case logrus.InfoLevel:
+		return slog.LevelInfo
+	case logrus.WarnLevel:
+		return slog.LevelWarn
+	case logrus.ErrorLevel:
+		return slog.LevelError
+	case logrus.FatalLevel:
+		return SlogLevelFatal
+	default:
+		return SlogLevelPanic
+	}
+}
@@ -0,0 +1,88 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"log/slog"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/jedrp/go-core/config"
+	"github.com/sirupsen/logrus"
+)
+
+func newJSONTestLogger(t *testing.T, buf *bytes.Buffer) *LogrusLogger {
+	logger := newTestLogger(t, newLevelRegistry(), config.Section{"level": "trace", "format": "json", "caller": "true"})
+	logger.SetOutput(buf)
+	return logger
+}
+
+func decodeLines(t *testing.T, buf *bytes.Buffer) []map[string]interface{} {
+	t.Helper()
+	var entries []map[string]interface{}
+	for _, line := range strings.Split(strings.TrimSpace(buf.String()), "\n") {
+		entry := map[string]interface{}{}
+		if err := json.Unmarshal([]byte(line), &entry); err != nil {
+			t.Fatalf("invalid line %q: %v", line, err)
+		}
+		entries = append(entries, entry)
+	}
+	return entries
+}
+
+func TestSlogHandler(t *testing.T) {
+	var buf bytes.Buffer
+	logger := slog.New(NewSlogHandler(newJSONTestLogger(t, &buf)))
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+
+	_, file, line, _ := runtime.Caller(0)
+	logger.With("a", 1).WithGroup("g").InfoContext(ctx, "info", "b", "x", slog.Group("h", "c", true))
+	logger.Log(ctx, SlogLevelPanic, "panic")
+	logger.Log(ctx, SlogLevelTrace, "trace")
+
+	entries := decodeLines(t, &buf)
+	if len(entries) != 3 {
+		t.Fatalf("expected 3 entries but got %d", len(entries))
+	}
+	expected := map[string]interface{}{
+		"message":   "info",
+		"level":     "info",
+		"a":         1.0,
+		"g.b":       "x",
+		"g.h.c":     true,
+		"requestId": "req-1",
+	}
+	for k, v := range expected {
+		if entries[0][k] != v {
+			t.Errorf("expected %s=%v but got %v", k, v, entries[0][k])
+		}
+	}
+	if caller, _ := entries[0]["caller"].(string); !strings.HasSuffix(caller, file+":"+strconv.Itoa(line+1)) {
+		t.Errorf("expected the caller of the slog call but got %s", caller)
+	}
+	// a handler must not panic or exit, panic records are logged as fatal without exiting
+	if entries[1]["level"] != "fatal" || entries[2]["level"] != "trace" {
+		t.Errorf("expected fatal and trace levels but got %v and %v", entries[1]["level"], entries[2]["level"])
+	}
+}
+
+func TestSlogHandlerEnabled(t *testing.T) {
+	logger := newTestLogger(t, newLevelRegistry(), config.Section{"level": "warn"})
+	handler := NewSlogHandler(logger)
+	for level, enabled := range map[slog.Level]bool{slog.LevelInfo: false, slog.LevelWarn: true, SlogLevelFatal: true} {
+		if handler.Enabled(context.Background(), level) != enabled {
+			t.Errorf("expected %v enabled %v", level, enabled)
+		}
+	}
+}
+
+func TestSlogLevelMapping(t *testing.T) {
+	for _, level := range logrus.AllLevels {
+		if l := logrusLevel(slogLevel(level)); l != level {
+			t.Errorf("expected %s to round trip but got %s", level, l)
+		}
+	}
+}
@@ -0,0 +1,180 @@
+package log
+
+import (
+	"context"
+	"fmt"
+	"log/slog"
+	"os"
+	"runtime"
+	"time"
+
+	"github.com/sirupsen/logrus"
+)
+
+// SlogLogger is a Logger writing to a slog.Handler, the fields are written as attributes.
+// Fatal logs exit the process and Panic logs panic after the record is handled, as with logrus.
+type SlogLogger struct {
+	handler slog.Handler
+	attrs   []slog.Attr
+	name    string
+}
+
+// NewSlogLogger returns a Logger writing to handler
+func NewSlogLogger(handler slog.Handler) *SlogLogger {
+	return &SlogLogger{handler: handler}
+}
+
+func (l *SlogLogger) IsLevelEnabled(level logrus.Level) bool {
+	return l.enabled(context.Background(), level)
+}
+
+// enabled checks the level of the handler and, for a named logger whose name or parent names have a level
+// in the "log" section or set by SetLevel, that level
+func (l *SlogLogger) enabled(ctx context.Context, level logrus.Level) bool {
+	if l.name != "" {
+		if explicit, found := defaultLevels.explicitLevel(l.name); found && explicit < level {
+			return false
+		}
+	}
+	return l.handler.Enabled(ctx, slogLevel(level))
+}
+
+// WithFields returns a logger adding the fields to its records
+func (l *SlogLogger) WithFields(fields map[string]interface{}) LogEntry {
+	attrs := make([]slog.Attr, 0, len(l.attrs)+len(fields))
+	attrs = append(attrs, l.attrs...)
+	for k, v := range fields {
+		attrs = append(attrs, slog.Any(k, v))
+	}
+	return &SlogLogger{handler: l.handler, attrs: attrs, name: l.name}
+}
+
+// Named returns a logger adding the Component attribute to its records, its records are also
+// filtered by the level of the name, or of its nearest parent name, set by level.<name> of the "log" section
+// or by SetLevel. The level of the root only applies to the handler of the logger.
+func (l *SlogLogger) Named(name string) Logger {
+	if l.name != "" {
+		name = l.name + "." + name
+	}
+	attrs := make([]slog.Attr, 0, len(l.attrs)+1)
+	for _, a := range l.attrs {
+		if a.Key != Component {
+			attrs = append(attrs, a)
+		}
+	}
+	attrs = append(attrs, slog.String(Component, name))
+	return &SlogLogger{handler: l.handler, attrs: attrs, name: name}
+}
+
+// log handles the record, skipping the frames of the logger methods for its source
+func (l *SlogLogger) log(ctx context.Context, level logrus.Level, msg string) {
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	slevel := slogLevel(level)
+	if l.enabled(ctx, level) {
+		var pcs [1]uintptr
+		// skip runtime.Callers, log and the logger method
+		runtime.Callers(3, pcs[:])
+		record := slog.NewRecord(time.Now(), slevel, msg, pcs[0])
+		record.AddAttrs(l.attrs...)
+		for k, v := range FieldsFromContext(ctx) {
+			record.AddAttrs(slog.Any(k, v))
+		}
+		l.handler.Handle(ctx, record)
+	}
+	switch level {
+	case logrus.FatalLevel:
+		os.Exit(1)
+	case logrus.PanicLevel:
+		panic(msg)
+	}
+}
+
+func (l *SlogLogger) Trace(args ...interface{}) {
+	l.log(context.Background(), logrus.TraceLevel, fmt.Sprint(args...))
+}
+func (l *SlogLogger) Debug(args ...interface{}) {
+	l.log(context.Background(), logrus.DebugLevel, fmt.Sprint(args...))
+}
+func (l *SlogLogger) Info(args ...interface{}) {
+	l.log(context.Background(), logrus.InfoLevel, fmt.Sprint(args...))
+}
+func (l *SlogLogger) Warn(args ...interface{}) {
+	l.log(context.Background(), logrus.WarnLevel, fmt.Sprint(args...))
+}
+func (l *SlogLogger) Error(args ...interface{}) {
+	l.log(context.Background(), logrus.ErrorLevel, fmt.Sprint(args...))
+}
+func (l *SlogLogger) Fatal(args ...interface{}) {
+	l.log(context.Background(), logrus.FatalLevel, fmt.Sprint(args...))
+}
+func (l *SlogLogger) Panic(args ...interface{}) {
+	l.log(context.Background(), logrus.PanicLevel, fmt.Sprint(args...))
+}
+
+func (l *SlogLogger) Tracef(format string, args ...interface{}) {
+	l.log(context.Background(), logrus.TraceLevel, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) Debugf(format string, args ...interface{}) {
+	l.log(context.Background(), logrus.DebugLevel, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) Infof(format string, args ...interface{}) {
+	l.log(context.Background(), logrus.InfoLevel, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) Warnf(format string, args ...interface{}) {
+	l.log(context.Background(), logrus.WarnLevel, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) Errorf(format string, args ...interface{}) {
+	l.log(context.Background(), logrus.ErrorLevel, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) Fatalf(format string, args ...interface{}) {
+	l.log(context.Background(), logrus.FatalLevel, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) Panicf(format string, args ...interface{}) {
+	l.log(context.Background(), logrus.PanicLevel, fmt.Sprintf(format, args...))
+}
+
+func (l *SlogLogger) TraceWithContext(ctx context.Context, args ...interface{}) {
+	l.log(ctx, logrus.TraceLevel, fmt.Sprint(args...))
+}
+func (l *SlogLogger) DebugWithContext(ctx context.Context, args ...interface{}) {
+	l.log(ctx, logrus.DebugLevel, fmt.Sprint(args...))
+}
+func (l *SlogLogger) InfoWithContext(ctx context.Context, args ...interface{}) {
+	l.log(ctx, logrus.InfoLevel, fmt.Sprint(args...))
+}
+func (l *SlogLogger) WarnWithContext(ctx context.Context, args ...interface{}) {
+	l.log(ctx, logrus.WarnLevel, fmt.Sprint(args...))
+}
+func (l *SlogLogger) ErrorWithContext(ctx context.Context, args ...interface{}) {
+	l.log(ctx, logrus.ErrorLevel, fmt.Sprint(args...))
+}
+func (l *SlogLogger) FatalWithContext(ctx context.Context, args ...interface{}) {
+	l.log(ctx, logrus.FatalLevel, fmt.Sprint(args...))
+}
+func (l *SlogLogger) PanicWithContext(ctx context.Context, args ...interface{}) {
+	l.log(ctx, logrus.PanicLevel, fmt.Sprint(args...))
+}
+
+func (l *SlogLogger) TracefWithContext(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, logrus.TraceLevel, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) DebugfWithContext(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, logrus.DebugLevel, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) InfofWithContext(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, logrus.InfoLevel, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) WarnfWithContext(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, logrus.WarnLevel, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) ErrorfWithContext(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, logrus.ErrorLevel, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) FatalfWithContext(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, logrus.FatalLevel, fmt.Sprintf(format, args...))
+}
+func (l *SlogLogger) PanicfWithContext(ctx context.Context, format string, args ...interface{}) {
+	l.log(ctx, logrus.PanicLevel, fmt.Sprintf(format, args...))
+}
@@ -0,0 +1,124 @@
+package log
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"log/slog"
+	"path/filepath"
+	"runtime"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/sirupsen/logrus"
+)
+
+func TestNamedSlogLoggerLevel(t *testing.T) {
+	t.Cleanup(func() {
+		ResetLevel("slogtest")
+		ResetLevel(RootLoggerName)
+	})
+	var buf bytes.Buffer
+	logger := NewSlogLogger(slog.NewTextHandler(&buf, &slog.HandlerOptions{Level: slog.LevelDebug}))
+	named := logger.Named("slogtest").Named("child")
+
+	// the root level only applies to logrus loggers
+	SetLevel(RootLoggerName, logrus.ErrorLevel, 0)
+	named.Debug("unconfigured")
+	SetLevel("slogtest", logrus.InfoLevel, 0)
+	named.Debug("filtered")
+	named.Info("configured")
+
+	out := buf.String()
+	if !strings.Contains(out, "msg=unconfigured") || strings.Contains(out, "filtered") || !strings.Contains(out, "msg=configured") {
+		t.Errorf("unexpected output %q", out)
+	}
+	if !strings.Contains(out, "Component=slogtest.child") {
+		t.Errorf("expected the component of the nested name in %q", out)
+	}
+}
+
+// recordHandler keeps the handled records
+type recordHandler struct {
+	records *[]slog.Record
+}
+
+func (h recordHandler) Enabled(context.Context, slog.Level) bool { return true }
+
+func (h recordHandler) Handle(_ context.Context, r slog.Record) error {
+	*h.records = append(*h.records, r)
+	return nil
+}
+
+func (h recordHandler) WithAttrs([]slog.Attr) slog.Handler { return h }
+
+func (h recordHandler) WithGroup(string) slog.Handler { return h }
+
+func recordSource(r slog.Record) string {
+	frame, _ := runtime.CallersFrames([]uintptr{r.PC}).Next()
+	return filepath.Base(frame.File) + ":" + strconv.Itoa(frame.Line)
+}
+
+func recordAttrs(r slog.Record) map[string]interface{} {
+	attrs := map[string]interface{}{}
+	r.Attrs(func(a slog.Attr) bool {
+		attrs[a.Key] = a.Value.Any()
+		return true
+	})
+	return attrs
+}
+
+func TestSlogLogger(t *testing.T) {
+	var records []slog.Record
+	logger := NewSlogLogger(recordHandler{&records})
+	ctx := ContextWithRequestID(context.Background(), "req-1")
+
+	_, file, line, _ := runtime.Caller(0)
+	logger.Info("info")
+	logger.Warnf("warn %d", 1)
+	logger.ErrorWithContext(ctx, "error")
+	logger.WithFields(map[string]interface{}{"a": 1}).Debug("debug")
+	logger.Named("cqs").Tracef("trace")
+
+	expected := []struct {
+		level slog.Level
+		msg   string
+		attrs map[string]interface{}
+	}{
+		{level: slog.LevelInfo, msg: "info"},
+		{level: slog.LevelWarn, msg: "warn 1"},
+		{level: slog.LevelError, msg: "error", attrs: map[string]interface{}{RequestID: "req-1"}},
+		{level: slog.LevelDebug, msg: "debug", attrs: map[string]interface{}{"a": 1}},
+		{level: SlogLevelTrace, msg: "trace", attrs: map[string]interface{}{Component: "cqs"}},
+	}
+	if len(records) != len(expected) {
+		t.Fatalf("expected %d records but got %d", len(expected), len(records))
+	}
+	for i, e := range expected {
+		r := records[i]
+		if r.Level != e.level || r.Message != e.msg {
+			t.Errorf("#%d: expected %v %q but got %v %q", i, e.level, e.msg, r.Level, r.Message)
+		}
+		// the source is the caller of the logger method
+		if source := filepath.Base(file) + ":" + strconv.Itoa(line+1+i); recordSource(r) != source {
+			t.Errorf("#%d: expected source %s but got %s", i, source, recordSource(r))
+		}
+		attrs := recordAttrs(r)
+		for k, v := range e.attrs {
+			if fmt.Sprint(attrs[k]) != fmt.Sprint(v) {
+				t.Errorf("#%d: expected %s=%v but got %v", i, k, v, attrs[k])
+			}
+		}
+	}
+}
+
+func TestSlogLoggerPanic(t *testing.T) {
+	var records []slog.Record
+	defer func() {
+		if r := recover(); r != "boom" || len(records) != 1 || records[0].Level != SlogLevelPanic {
+			t.Errorf("expected the record to be handled before panicking but got %v and %d records", r, len(records))
+		}
+	}()
+	NewSlogLogger(recordHandler{&records}).Panic("boom")
+}
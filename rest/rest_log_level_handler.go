@@ -0,0 +1,73 @@
+package rest
+
+import (
+	"encoding/json"
+	"net/http"
+	"time"
+
+	"github.com/jedrp/go-core/log"
+	"github.com/jedrp/go-core/result"
+	"github.com/sirupsen/logrus"
+)
+
+// LogLevel is a level of the log level admin endpoint, the root level has an empty name
+type LogLevel struct {
+	Name  string `json:"name"`
+	Level string `json:"level"`
+	// RevertAt is set when the level is temporary
+	RevertAt *time.Time `json:"revertAt,omitempty"`
+	// RevertAfter duration before the previous level is restored, e.g. "15m", only read by PUT
+	RevertAfter string `json:"revertAfter,omitempty"`
+}
+
+// LogLevelHandler is the admin endpoint of the log levels, see log.SetLevel.
+//
+//	GET                                                      lists the levels
+//	PUT {"name": "cqs", "level": "debug", "revertAfter": "15m"} changes a level, temporarily when revertAfter is set
+//	DELETE ?name=cqs                                         restores the configured level of the name
+//
+// It changes the levels of the whole process and must be served on an admin port or behind authorization.
+func LogLevelHandler() http.Handler {
+	return result.HandlerFunc(func(r *http.Request) *result.Result {
+		switch r.Method {
+		case http.MethodGet:
+		case http.MethodPut, http.MethodPost:
+			var req LogLevel
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				return result.FailWith(result.NewInvalidArgument("invalid request body: %v", err))
+			}
+			v := result.NewValidation()
+			level, err := logrus.ParseLevel(req.Level)
+			v.Check(err == nil, "level", "enum", "level must be one of trace, debug, info, warn, error, fatal, panic", nil)
+			var revertAfter time.Duration
+			if req.RevertAfter != "" {
+				revertAfter, err = time.ParseDuration(req.RevertAfter)
+				v.Check(err == nil && revertAfter > 0, "revertAfter", "duration", "revertAfter must be a positive duration, e.g. 15m", nil)
+			}
+			if err := v.Err(); err != nil {
+				return result.FailWith(err)
+			}
+			log.SetLevel(req.Name, level, revertAfter)
+		case http.MethodDelete:
+			log.ResetLevel(r.URL.Query().Get("name"))
+		default:
+			return result.FailWith(result.NewMethodNotAllowed("method %s is not allowed", r.Method)).
+				WithHeader("Allow", "GET, PUT, POST, DELETE")
+		}
+		return result.OK(logLevels()).WithHeader("Cache-Control", "no-store")
+	})
+}
+
+func logLevels() map[string][]LogLevel {
+	settings := log.Levels()
+	levels := make([]LogLevel, 0, len(settings))
+	for _, s := range settings {
+		l := LogLevel{Name: s.Name, Level: s.Level.String()}
+		if !s.RevertAt.IsZero() {
+			revertAt := s.RevertAt
+			l.RevertAt = &revertAt
+		}
+		levels = append(levels, l)
+	}
+	return map[string][]LogLevel{"levels": levels}
+}
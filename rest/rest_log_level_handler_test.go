@@ -0,0 +1,47 @@
+package rest
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jedrp/go-core/log"
+	"github.com/sirupsen/logrus"
+)
+
+func TestLogLevelHandler(t *testing.T) {
+	t.Cleanup(func() { log.ResetLevel("resttest") })
+	tt := []struct {
+		method string
+		target string
+		body   string
+		status int
+		level  logrus.Level
+		// expected in the response body
+		contains string
+	}{
+		{method: "PUT", target: "/", body: `{"name":"resttest","level":"trace"}`, status: 200, level: logrus.TraceLevel, contains: `{"name":"resttest","level":"trace"}`},
+		{method: "PUT", target: "/", body: `{"name":"resttest","level":"verbose"}`, status: 400, level: logrus.TraceLevel, contains: `"field":"level"`},
+		{method: "PUT", target: "/", body: `{"name":"resttest","level":"info","revertAfter":"soon"}`, status: 400, level: logrus.TraceLevel, contains: `"field":"revertAfter"`},
+		{method: "PUT", target: "/", body: `{"name":"resttest","level":"info","revertAfter":"-1m"}`, status: 400, level: logrus.TraceLevel, contains: `"field":"revertAfter"`},
+		{method: "PUT", target: "/", body: `{"name":`, status: 400, level: logrus.TraceLevel, contains: "invalid request body"},
+		{method: "POST", target: "/", body: `{"name":"resttest","level":"warn","revertAfter":"1h"}`, status: 200, level: logrus.WarnLevel, contains: `"revertAt"`},
+		{method: "GET", target: "/", status: 200, level: logrus.WarnLevel, contains: `"name":"resttest","level":"warning","revertAt"`},
+		{method: "PATCH", target: "/", status: 405, level: logrus.WarnLevel, contains: "MethodNotAllowed"},
+		{method: "DELETE", target: "/?name=resttest", status: 200, level: log.GetLevel(log.RootLoggerName)},
+	}
+	for _, tc := range tt {
+		rw := httptest.NewRecorder()
+		LogLevelHandler().ServeHTTP(rw, httptest.NewRequest(tc.method, tc.target, strings.NewReader(tc.body)))
+		if rw.Code != tc.status || !strings.Contains(rw.Body.String(), tc.contains) {
+			t.Errorf("%s %s: expected %d with %s but got %d %s", tc.method, tc.body, tc.status, tc.contains, rw.Code, rw.Body.String())
+		}
+		if l := log.GetLevel("resttest"); l != tc.level {
+			t.Errorf("%s %s: expected level %s but got %s", tc.method, tc.body, tc.level, l)
+		}
+		if tc.status == http.StatusMethodNotAllowed && rw.Header().Get("Allow") == "" {
+			t.Error("expected the Allow header")
+		}
+	}
+}
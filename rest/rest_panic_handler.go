@@ -7,6 +7,7 @@ import (
 	"runtime/debug"
 
 	"github.com/jedrp/go-core/log"
+	"github.com/jedrp/go-core/result"
 	uuid "github.com/satori/go.uuid"
 )
 
@@ -16,7 +17,7 @@ func HandlePanicMiddleware(handler http.Handler, logger log.Logger) http.Handler
 		defer func() {
 			if rErr := recover(); rErr != nil {
 				if logger != nil {
-					log.CreateRequestLogEntryFromContext(ctx, logger).Error(rErr, string(debug.Stack()))
+					log.CreateRequestLogEntryFromContext(log.ContextWithFields(ctx, map[string]interface{}{log.Stack: string(debug.Stack())}), logger).Error(rErr)
 				}
 				w.Header().Set("Content-Type", "application/json")
 				w.WriteHeader(500)
@@ -32,14 +33,18 @@ func NewRequestContext(r *http.Request) context.Context {
 	ctx := r.Context()
 	reqID := r.Header.Get(log.RequestIDHeaderKey)
 	if reqID != "" {
-		ctx = context.WithValue(ctx, log.RequestID, reqID)
+		ctx = log.ContextWithRequestID(ctx, reqID)
 	} else {
-		ctx = context.WithValue(ctx, log.RequestID, uuid.NewV4().String())
+		ctx = log.ContextWithRequestID(ctx, uuid.NewV4().String())
 	}
 
 	corID := r.Header.Get(log.CorrelationIDHeaderKey)
 	if corID != "" {
-		ctx = context.WithValue(ctx, log.CorrelationID, corID)
+		ctx = log.ContextWithCorrelationID(ctx, corID)
+	}
+
+	if lang := r.Header.Get("Accept-Language"); lang != "" {
+		ctx = result.ContextWithLanguage(ctx, lang)
 	}
 	return ctx
 }
@@ -0,0 +1,37 @@
+package rest
+
+import (
+	"bytes"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/jedrp/go-core/log"
+)
+
+func TestHandlePanicMiddleware(t *testing.T) {
+	var buf bytes.Buffer
+	logger := log.NewSlogLogger(slog.NewJSONHandler(&buf, nil))
+	handler := HandlePanicMiddleware(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), logger)
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/", nil))
+	if rec.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 but got %d", rec.Code)
+	}
+	var entry map[string]interface{}
+	if err := json.Unmarshal(buf.Bytes(), &entry); err != nil {
+		t.Fatal(err)
+	}
+	if entry["msg"] != "boom" {
+		t.Errorf("expected the panic value as message but got %v", entry["msg"])
+	}
+	if stack, _ := entry[log.Stack].(string); !strings.Contains(stack, "TestHandlePanicMiddleware") {
+		t.Errorf("expected the stack in the %s field but got %q", log.Stack, stack)
+	}
+}
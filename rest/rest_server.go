@@ -7,104 +7,96 @@ import (
 	"io/ioutil"
 	"net"
 	"net/http"
-	"os"
-	"strconv"
+	"sync/atomic"
 	"time"
 
+	"github.com/jedrp/go-core/config"
 	"github.com/jedrp/go-core/log"
 	"github.com/jedrp/go-core/util"
-	"github.com/jessevdk/go-flags"
 	"golang.org/x/net/netutil"
 )
 
 // Server wrapper object to run rest service
 // setting string : SERVER_CONFIG|server-config="host=0.0.0.0;port=80;tlsCert=;tlsCertKey=;"
 type Server struct {
-	SettingStr     string `long:"server-config" description:"the server setting string" env:"SERVER_CONFIG" json:"settingStr,omitempty"`
-	host           string
-	port           string
-	logger         log.Logger
-	httpServer     *http.Server
-	listenLimit    int
-	cleanupTimeout int
-	keepAlive      int
-	readTimeout    int
-	writeTimeout   int
-	listener       net.Listener
+	SettingStr string `json:"settingStr,omitempty"`
+	settings   serverSettings
+	logger     log.Logger
+	httpServer *http.Server
+	listener   net.Listener
+	// applied to each request so they can be changed while serving
+	readTimeout  atomic.Int64
+	writeTimeout atomic.Int64
 }
 
-const (
-	tlsCACert      = "tlsCACert"
-	tlsCert        = "tlsCert"
-	tlsCertKey     = "tlsCertKey"
-	host           = "host"
-	port           = "port"
-	listenLimit    = "listenLimit"
-	cleanupTimeout = "cleanupTimeout"
-	readTimeout    = "readTimeout"
-	writeTimeout   = "writeTimeout"
-	keepAlive      = "keepAlive"
-)
+// serverSettings timeouts given as bare numbers are in seconds
+type serverSettings struct {
+	Host           string        `setting:"host" description:"the interface to listen on"`
+	Port           string        `setting:"port" default:"0" description:"the port to listen on"`
+	TLSCACert      string        `setting:"tlsCACert" description:"the CA certificate file verifying the client certificates"`
+	TLSCert        string        `setting:"tlsCert" description:"the certificate file"`
+	TLSCertKey     string        `setting:"tlsCertKey" description:"the certificate key file"`
+	ListenLimit    int           `setting:"listenLimit" description:"the maximum number of simultaneous connections"`
+	CleanupTimeout time.Duration `setting:"cleanupTimeout" unit:"s" description:"the idle timeout of keep-alive connections"`
+	ReadTimeout    time.Duration `setting:"readTimeout" default:"30" unit:"s" description:"the read timeout of a request"`
+	WriteTimeout   time.Duration `setting:"writeTimeout" default:"60" unit:"s" description:"the write timeout of a response"`
+	KeepAlive      time.Duration `setting:"keepAlive" default:"180" unit:"s" description:"keep-alive connections are disabled when 0"`
+}
+
+func init() {
+	config.RegisterSchema(config.ServerSection, util.SchemaOf(serverSettings{}))
+}
 
+// NewServer creates the server from the "server" section of config.Default(), its timeouts follow the reloads of config.Watch()
 func NewServer(handler http.Handler, logger log.Logger) *Server {
+	server := NewServerWithSettings(handler, logger, config.Default().Section(config.ServerSection))
+	server.Watch(config.Watch())
+	return server
+}
+
+// NewServerWithSettings creates the server from the given settings, see the setting string of Server
+func NewServerWithSettings(handler http.Handler, logger log.Logger, settings config.Section) *Server {
 	server := &Server{
-		logger:       logger,
-		readTimeout:  30,
-		writeTimeout: 60,
-		keepAlive:    180,
+		SettingStr: settings.Redacted(),
+		logger:     logger,
 	}
 
-	parser := flags.NewParser(server, flags.IgnoreUnknown)
-	parseConfig(parser)
-
 	if server.SettingStr == "" {
 		logger.Warn("empty server setting string")
 	} else {
 		logger.Infof("server start with setting string: %v", server.SettingStr)
 	}
 
-	config, err := util.GetConfig(server.SettingStr)
-
-	if v, found := config[host]; found {
-		server.host = v
+	if err := util.Bind(settings, &server.settings); err != nil {
+		panic(err)
 	}
-
-	if v, found := config[port]; found {
-		server.port = v
-	} else {
-		server.port = "0"
+	for _, key := range util.UnknownKeys(settings, util.SchemaOf(serverSettings{})) {
+		logger.Warnf("unknown server setting %s is ignored", key)
 	}
+	s := server.settings
 
-	setConfigInt(config, &server.listenLimit, listenLimit)
-	setConfigInt(config, &server.cleanupTimeout, cleanupTimeout)
-	setConfigInt(config, &server.readTimeout, readTimeout)
-	setConfigInt(config, &server.writeTimeout, writeTimeout)
-	setConfigInt(config, &server.keepAlive, keepAlive)
-
-	cert := config[tlsCert]
-	certKey := config[tlsCertKey]
-	certCA := config[tlsCACert]
-
-	listener, err := net.Listen("tcp", net.JoinHostPort(server.host, server.port))
+	listener, err := net.Listen("tcp", net.JoinHostPort(s.Host, s.Port))
 
 	if err != nil {
 		panic(err)
 	}
 	httpServer := new(http.Server)
 	httpServer.MaxHeaderBytes = 1024
-	httpServer.ReadTimeout = time.Duration(server.readTimeout) * time.Second
-	httpServer.WriteTimeout = time.Duration(server.writeTimeout) * time.Second
-	httpServer.SetKeepAlivesEnabled(int64(server.keepAlive) > 0)
-	if server.listenLimit > 0 {
-		listener = netutil.LimitListener(listener, server.listenLimit)
+	httpServer.ReadTimeout = s.ReadTimeout
+	httpServer.WriteTimeout = s.WriteTimeout
+	httpServer.SetKeepAlivesEnabled(s.KeepAlive > 0)
+	if s.ListenLimit > 0 {
+		listener = netutil.LimitListener(listener, s.ListenLimit)
 	}
-	if server.cleanupTimeout > 0 {
-		httpServer.IdleTimeout = time.Duration(server.cleanupTimeout) * time.Second
+	if s.CleanupTimeout > 0 {
+		httpServer.IdleTimeout = s.CleanupTimeout
 	}
-	httpServer.Handler = HandlePanicMiddleware(handler, logger)
+	server.readTimeout.Store(int64(s.ReadTimeout))
+	server.writeTimeout.Store(int64(s.WriteTimeout))
+	httpServer.Handler = server.handleDeadlines(HandlePanicMiddleware(handler, logger))
 
 	//https
-	if cert != "" && certKey != "" {
+	if s.TLSCert != "" && s.TLSCertKey != "" {
 		httpServer.TLSConfig = &tls.Config{
 			// Causes servers to use Go's default ciphersuite preferences,
 			// which are tuned to avoid attacks. Does nothing on clients.
@@ -128,14 +120,14 @@ func NewServer(handler http.Handler, logger log.Logger) *Server {
 		}
 		// build standard config from server options
 		httpServer.TLSConfig.Certificates = make([]tls.Certificate, 1)
-		httpServer.TLSConfig.Certificates[0], err = tls.LoadX509KeyPair(string(cert), string(certKey))
+		httpServer.TLSConfig.Certificates[0], err = tls.LoadX509KeyPair(s.TLSCert, s.TLSCertKey)
 		if err != nil {
 			panic(err)
 		}
 
-		if certCA != "" {
+		if s.TLSCACert != "" {
 			// include specified CA certificate
-			caCert, caCertErr := ioutil.ReadFile(string(certCA))
+			caCert, caCertErr := ioutil.ReadFile(s.TLSCACert)
 			if caCertErr != nil {
 				panic(caCertErr)
 			}
@@ -161,6 +153,36 @@ func NewServer(handler http.Handler, logger log.Logger) *Server {
 	return server
 }
 
+// Watch applies the read and write timeouts of the "server" section when w reloads it,
+// the other settings require a restart
+func (s *Server) Watch(w *config.Watcher) {
+	config.OnChange(w, config.ServerSection, func(settings serverSettings) {
+		s.readTimeout.Store(int64(settings.ReadTimeout))
+		s.writeTimeout.Store(int64(settings.WriteTimeout))
+		s.logger.Infof("server timeouts changed to read %v, write %v", settings.ReadTimeout, settings.WriteTimeout)
+	})
+}
+
+// handleDeadlines overrides the connection deadlines set from the initial timeouts of the http server,
+// a timeout of 0 clears the deadline
+func (s *Server) handleDeadlines(handler http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		rc := http.NewResponseController(w)
+		now := time.Now()
+		rc.SetReadDeadline(deadline(now, time.Duration(s.readTimeout.Load())))
+		rc.SetWriteDeadline(deadline(now, time.Duration(s.writeTimeout.Load())))
+		handler.ServeHTTP(w, r)
+	})
+}
+
+// deadline returns the zero time, meaning no deadline, when the timeout is not positive
+func deadline(now time.Time, timeout time.Duration) time.Time {
+	if timeout <= 0 {
+		return time.Time{}
+	}
+	return now.Add(timeout)
+}
+
 func (s *Server) Serve() error {
 	s.logger.Infof("Sever starting serving at: %s", s.listener.Addr())
 	if err := s.httpServer.Serve(s.listener); err != nil && err != http.ErrServerClosed {
@@ -169,23 +191,3 @@ func (s *Server) Serve() error {
 	}
 	return nil
 }
-func setConfigInt(config map[string]string, settingField *int, key string) {
-	if v, found := config[key]; found {
-		intValue, err := strconv.Atoi(v)
-		if err != nil {
-			panic(err)
-		}
-		*settingField = intValue
-	}
-}
-func parseConfig(parser *flags.Parser) {
-	if _, err := parser.Parse(); err != nil {
-		code := 1
-		if fe, ok := err.(*flags.Error); ok {
-			if fe.Type == flags.ErrHelp {
-				code = 0
-			}
-		}
-		os.Exit(code)
-	}
-}
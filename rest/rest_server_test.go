@@ -0,0 +1,48 @@
+package rest
+
+import (
+	"io"
+	"log/slog"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/jedrp/go-core/config"
+	"github.com/jedrp/go-core/log"
+)
+
+func TestWatchDisablesTimeouts(t *testing.T) {
+	settings := config.Section{"host": "127.0.0.1", "readTimeout": "100ms", "writeTimeout": "100ms"}
+	w, err := config.NewWatcher(config.SourceFunc(func() (map[string]config.Section, error) {
+		return map[string]config.Section{config.ServerSection: settings}, nil
+	}))
+	if err != nil {
+		t.Fatal(err)
+	}
+	logger := log.NewSlogLogger(slog.NewTextHandler(io.Discard, nil))
+	server := NewServerWithSettings(http.HandlerFunc(func(rw http.ResponseWriter, r *http.Request) {
+		time.Sleep(300 * time.Millisecond)
+		rw.Write([]byte("done"))
+	}), logger, settings)
+	server.Watch(w)
+	go server.Serve()
+	t.Cleanup(func() { server.httpServer.Close() })
+	url := "http://" + server.listener.Addr().String()
+
+	if _, err := http.Get(url); err == nil {
+		t.Fatal("expected the write timeout to drop the response")
+	}
+
+	settings = config.Section{"host": "127.0.0.1", "readTimeout": "0", "writeTimeout": "0"}
+	if err := w.Reload(); err != nil {
+		t.Fatal(err)
+	}
+	resp, err := http.Get(url)
+	if err != nil {
+		t.Fatalf("expected the timeouts to be disabled but got %v", err)
+	}
+	defer resp.Body.Close()
+	if b, _ := io.ReadAll(resp.Body); string(b) != "done" {
+		t.Errorf("expected the response but got %q", b)
+	}
+}
@@ -0,0 +1,199 @@
+package result
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// AcceptLanguageMetadataKey is the gRPC metadata key holding the preferred languages of the client
+const AcceptLanguageMetadataKey = "accept-language"
+
+// Reason is an error registered in a Catalogue, identified by a stable ID.
+// Messages are templates where "{name}" is replaced by the argument of that name.
+type Reason struct {
+	ID      string    `json:"id"`
+	Code    ErrorCode `json:"code"`
+	Message string    `json:"message"`
+	// Translations of Message by language tag, e.g. "fr" or "pt-BR"
+	Translations map[string]string `json:"translations,omitempty"`
+}
+
+// Catalogue holds the error reasons of a service,
+// its errors carry a google.rpc.ErrorInfo with the reason ID and the catalogue domain
+type Catalogue struct {
+	domain  string
+	mux     sync.RWMutex
+	reasons map[string]Reason
+}
+
+// DefaultCatalogue catalogue without domain, services usually create their own with NewCatalogue
+var DefaultCatalogue = NewCatalogue("")
+
+func NewCatalogue(domain string) *Catalogue {
+	return &Catalogue{
+		domain:  domain,
+		reasons: make(map[string]Reason),
+	}
+}
+
+func (c *Catalogue) Register(reason Reason) error {
+	if reason.ID == "" {
+		return fmt.Errorf("reason id must have a value")
+	}
+	if reason.Code == "" {
+		return fmt.Errorf("reason %s must have a code", reason.ID)
+	}
+	translations := make(map[string]string, len(reason.Translations))
+	for lang, m := range reason.Translations {
+		translations[strings.ToLower(lang)] = m
+	}
+	reason.Translations = translations
+
+	c.mux.Lock()
+	defer c.mux.Unlock()
+	if _, found := c.reasons[reason.ID]; found {
+		return fmt.Errorf("duplicated reason %s", reason.ID)
+	}
+	c.reasons[reason.ID] = reason
+	return nil
+}
+
+// Error returns the error of the reason with its message rendered in the language of ctx,
+// see ContextWithLanguage. An unregistered reason gives an Unknown error.
+func (c *Catalogue) Error(ctx context.Context, id string, args map[string]interface{}) *Error {
+	c.mux.RLock()
+	reason, found := c.reasons[id]
+	c.mux.RUnlock()
+	if !found {
+		return NewErrorf(Unknown, "unknown error reason %s", id)
+	}
+
+	template := reason.Message
+	for _, lang := range LanguagesFromContext(ctx) {
+		if m, ok := reason.lookup(lang); ok {
+			template = m
+			break
+		}
+	}
+
+	info := &ErrorInfo{Reason: reason.ID, Domain: c.domain}
+	if len(args) > 0 {
+		info.Metadata = make(map[string]string, len(args))
+		for k, v := range args {
+			info.Metadata[k] = fmt.Sprint(v)
+		}
+	}
+	return NewError(reason.Code, render(template, info.Metadata)).WithDetails(info)
+}
+
+// Fail returns a failed result holding the error of the reason
+func (c *Catalogue) Fail(ctx context.Context, id string, args map[string]interface{}) *Result {
+	return &Result{Error: c.Error(ctx, id, args)}
+}
+
+// Reasons returns the registered reasons sorted by ID
+func (c *Catalogue) Reasons() []Reason {
+	c.mux.RLock()
+	defer c.mux.RUnlock()
+	reasons := make([]Reason, 0, len(c.reasons))
+	for _, r := range c.reasons {
+		reasons = append(reasons, r)
+	}
+	sort.Slice(reasons, func(i, j int) bool { return reasons[i].ID < reasons[j].ID })
+	return reasons
+}
+
+// Export writes the catalogue as JSON, e.g. to document the errors of a service
+func (c *Catalogue) Export(w io.Writer) error {
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(struct {
+		Domain  string   `json:"domain,omitempty"`
+		Reasons []Reason `json:"reasons"`
+	}{c.domain, c.Reasons()})
+}
+
+// lookup finds the translation of lang, falling back from a regional tag to its base language
+func (r Reason) lookup(lang string) (string, bool) {
+	if m, ok := r.Translations[lang]; ok {
+		return m, true
+	}
+	if base, _, found := strings.Cut(lang, "-"); found {
+		if m, ok := r.Translations[base]; ok {
+			return m, true
+		}
+	}
+	return "", false
+}
+
+func render(template string, args map[string]string) string {
+	if len(args) == 0 {
+		return template
+	}
+	replacements := make([]string, 0, len(args)*2)
+	for k, v := range args {
+		replacements = append(replacements, "{"+k+"}", v)
+	}
+	return strings.NewReplacer(replacements...).Replace(template)
+}
+
+type languageContextKey struct{}
+
+// ContextWithLanguage stores the preferred languages of the client, in the Accept-Language header format
+func ContextWithLanguage(ctx context.Context, acceptLanguage string) context.Context {
+	return context.WithValue(ctx, languageContextKey{}, acceptLanguage)
+}
+
+// LanguagesFromContext returns the preferred languages of the client, most preferred first, in lower case.
+// They are read from ContextWithLanguage or else the accept-language gRPC metadata.
+func LanguagesFromContext(ctx context.Context) []string {
+	if ctx == nil {
+		return nil
+	}
+	acceptLanguage, ok := ctx.Value(languageContextKey{}).(string)
+	if !ok {
+		if md, found := metadata.FromIncomingContext(ctx); found {
+			acceptLanguage = strings.Join(md.Get(AcceptLanguageMetadataKey), ",")
+		}
+	}
+	return parseAcceptLanguage(acceptLanguage)
+}
+
+func parseAcceptLanguage(acceptLanguage string) []string {
+	type weighted struct {
+		lang    string
+		quality float64
+	}
+	var langs []weighted
+	for _, part := range strings.Split(acceptLanguage, ",") {
+		// media type parser handles the "tag;q=0.8" syntax
+		lang, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || lang == "*" {
+			continue
+		}
+		quality := 1.0
+		if q, found := params["q"]; found {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		if quality > 0 {
+			langs = append(langs, weighted{strings.ToLower(lang), quality})
+		}
+	}
+	sort.SliceStable(langs, func(i, j int) bool { return langs[i].quality > langs[j].quality })
+	tags := make([]string, 0, len(langs))
+	for _, l := range langs {
+		tags = append(tags, l.lang)
+	}
+	return tags
+}
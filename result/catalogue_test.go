@@ -0,0 +1,58 @@
+package result
+
+import (
+	"bytes"
+	"context"
+	"strings"
+	"testing"
+
+	"google.golang.org/grpc/metadata"
+)
+
+func TestCatalogueLocalizedError(t *testing.T) {
+	c := NewCatalogue("users.example.com")
+	err := c.Register(Reason{
+		ID:      "USER_NOT_FOUND",
+		Code:    NotFound,
+		Message: "user {id} not found",
+		Translations: map[string]string{
+			"fr":    "utilisateur {id} introuvable",
+			"pt-BR": "usuário {id} não encontrado",
+		},
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := c.Register(Reason{ID: "USER_NOT_FOUND", Code: NotFound}); err == nil {
+		t.Error("expected duplicated error")
+	}
+
+	grpcCtx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(AcceptLanguageMetadataKey, "pt-br"))
+	tt := []struct {
+		ctx      context.Context
+		expected string
+	}{
+		{ctx: context.Background(), expected: "user 42 not found"},
+		{ctx: ContextWithLanguage(context.Background(), "de, fr-CH;q=0.9, en;q=0.8"), expected: "utilisateur 42 introuvable"},
+		{ctx: ContextWithLanguage(context.Background(), "fr;q=0, en"), expected: "user 42 not found"},
+		{ctx: grpcCtx, expected: "usuário 42 não encontrado"},
+	}
+	for i, tc := range tt {
+		e := c.Error(tc.ctx, "USER_NOT_FOUND", map[string]interface{}{"id": 42})
+		if e.Code != NotFound || e.Message != tc.expected {
+			t.Errorf("tc #%d, expected %q but got %v", i, tc.expected, e)
+		}
+		info, ok := e.Details[0].(*ErrorInfo)
+		if !ok || info.Reason != "USER_NOT_FOUND" || info.Domain != "users.example.com" || info.Metadata["id"] != "42" {
+			t.Errorf("tc #%d, unexpected detail %#v", i, e.Details[0])
+		}
+	}
+
+	var b bytes.Buffer
+	if err := c.Export(&b); err != nil {
+		t.Fatal(err)
+	}
+	if !strings.Contains(b.String(), `"id": "USER_NOT_FOUND"`) {
+		t.Errorf("expected reason in export but got %s", b.String())
+	}
+}
@@ -0,0 +1,78 @@
+package result
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+
+	"google.golang.org/grpc/codes"
+)
+
+type codeMapping struct {
+	httpStatus int
+	grpcCode   codes.Code
+}
+
+var (
+	codeMappingsMux sync.RWMutex
+	// canonical mapping, see https://github.com/googleapis/googleapis/blob/master/google/rpc/code.proto
+	codeMappings = map[ErrorCode]codeMapping{
+		Canceled:           {499, codes.Canceled},
+		Unknown:            {http.StatusInternalServerError, codes.Unknown},
+		InvalidArgument:    {http.StatusBadRequest, codes.InvalidArgument},
+		DeadlineExceeded:   {http.StatusGatewayTimeout, codes.DeadlineExceeded},
+		NotFound:           {http.StatusNotFound, codes.NotFound},
+		AlreadyExists:      {http.StatusConflict, codes.AlreadyExists},
+		PermissionDenied:   {http.StatusForbidden, codes.PermissionDenied},
+		ResourceExhausted:  {http.StatusTooManyRequests, codes.ResourceExhausted},
+		FailedPrecondition: {http.StatusBadRequest, codes.FailedPrecondition},
+		Aborted:            {http.StatusConflict, codes.Aborted},
+		OutOfRange:         {http.StatusBadRequest, codes.OutOfRange},
+		Unimplemented:      {http.StatusNotImplemented, codes.Unimplemented},
+		Internal:           {http.StatusInternalServerError, codes.Internal},
+		Unavailable:        {http.StatusServiceUnavailable, codes.Unavailable},
+		DataLoss:           {http.StatusInternalServerError, codes.DataLoss},
+		Unauthenticated:    {http.StatusUnauthorized, codes.Unauthenticated},
+		MethodNotAllowed:   {http.StatusMethodNotAllowed, codes.Unimplemented},
+	}
+)
+
+// RegisterErrorCode registers a service specific code with the HTTP and gRPC status it is written with
+func RegisterErrorCode(code ErrorCode, httpStatus int, grpcCode codes.Code) error {
+	if code == "" {
+		return fmt.Errorf("error code must have a value")
+	}
+	if httpStatus < 400 || httpStatus > 599 {
+		return fmt.Errorf("invalid http status %d for error code %s", httpStatus, code)
+	}
+	if grpcCode == codes.OK {
+		return fmt.Errorf("invalid grpc code %v for error code %s", grpcCode, code)
+	}
+	codeMappingsMux.Lock()
+	defer codeMappingsMux.Unlock()
+	if _, found := codeMappings[code]; found {
+		return fmt.Errorf("duplicated error code %s", code)
+	}
+	codeMappings[code] = codeMapping{httpStatus, grpcCode}
+	return nil
+}
+
+// HTTPStatus returns the HTTP status of code, 500 for unregistered codes
+func HTTPStatus(code ErrorCode) int {
+	codeMappingsMux.RLock()
+	defer codeMappingsMux.RUnlock()
+	if m, found := codeMappings[code]; found {
+		return m.httpStatus
+	}
+	return http.StatusInternalServerError
+}
+
+// GRPCCode returns the gRPC code of code, codes.Unknown for unregistered codes
+func GRPCCode(code ErrorCode) codes.Code {
+	codeMappingsMux.RLock()
+	defer codeMappingsMux.RUnlock()
+	if m, found := codeMappings[code]; found {
+		return m.grpcCode
+	}
+	return codes.Unknown
+}
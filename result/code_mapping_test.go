@@ -0,0 +1,74 @@
+package result
+
+import (
+	"testing"
+
+	"google.golang.org/grpc/codes"
+)
+
+func TestCodeMapping(t *testing.T) {
+	tt := []struct {
+		code       ErrorCode
+		httpStatus int
+		grpcCode   codes.Code
+	}{
+		{code: AlreadyExists, httpStatus: 409, grpcCode: codes.AlreadyExists},
+		{code: Aborted, httpStatus: 409, grpcCode: codes.Aborted},
+		{code: ResourceExhausted, httpStatus: 429, grpcCode: codes.ResourceExhausted},
+		{code: Unavailable, httpStatus: 503, grpcCode: codes.Unavailable},
+		{code: DeadlineExceeded, httpStatus: 504, grpcCode: codes.DeadlineExceeded},
+		{code: Unimplemented, httpStatus: 501, grpcCode: codes.Unimplemented},
+		{code: MethodNotAllowed, httpStatus: 405, grpcCode: codes.Unimplemented},
+		{code: "NotRegistered", httpStatus: 500, grpcCode: codes.Unknown},
+	}
+	for _, tc := range tt {
+		if s := HTTPStatus(tc.code); s != tc.httpStatus {
+			t.Errorf("%s: expected http status %d but got %d", tc.code, tc.httpStatus, s)
+		}
+		if c := GRPCCode(tc.code); c != tc.grpcCode {
+			t.Errorf("%s: expected grpc code %v but got %v", tc.code, tc.grpcCode, c)
+		}
+	}
+}
+
+func TestRegisterErrorCode(t *testing.T) {
+	const paymentRequired ErrorCode = "PaymentRequired"
+	if err := RegisterErrorCode(paymentRequired, 402, codes.FailedPrecondition); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() {
+		codeMappingsMux.Lock()
+		defer codeMappingsMux.Unlock()
+		delete(codeMappings, paymentRequired)
+	})
+	if err := RegisterErrorCode(paymentRequired, 402, codes.FailedPrecondition); err == nil {
+		t.Error("expected duplicated error")
+	}
+	if err := RegisterErrorCode(NotFound, 410, codes.NotFound); err == nil {
+		t.Error("expected built-in codes not to be overridden")
+	}
+	if HTTPStatus(paymentRequired) != 402 || GRPCCode(paymentRequired) != codes.FailedPrecondition {
+		t.Error("expected registered mapping to be used")
+	}
+}
+
+func TestCodeFromHTTPStatus(t *testing.T) {
+	tt := []struct {
+		httpStatus int
+		expected   ErrorCode
+	}{
+		{httpStatus: 400, expected: InvalidArgument},
+		{httpStatus: 404, expected: NotFound},
+		{httpStatus: 405, expected: MethodNotAllowed},
+		{httpStatus: 409, expected: Aborted},
+		{httpStatus: 418, expected: FailedPrecondition},
+		{httpStatus: 499, expected: Canceled},
+		{httpStatus: 503, expected: Unavailable},
+		{httpStatus: 599, expected: Unknown},
+	}
+	for _, tc := range tt {
+		if code := CodeFromHTTPStatus(tc.httpStatus); code != tc.expected {
+			t.Errorf("%d: expected %s but got %s", tc.httpStatus, tc.expected, code)
+		}
+	}
+}
@@ -120,3 +120,7 @@ const (
 
 	_maxErrorCode = 17
 )
+
+// MethodNotAllowed indicates the HTTP method is not supported by the resource. It is not a canonical
+// gRPC code, it is written with 405 over HTTP and Unimplemented over gRPC.
+const MethodNotAllowed ErrorCode = "MethodNotAllowed"
@@ -0,0 +1,188 @@
+package result
+
+import (
+	"context"
+	"encoding/xml"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/status"
+)
+
+type grpcStatus interface {
+	GRPCStatus() *status.Status
+}
+
+// Error is the failure of a Result, it implements error and can wrap a cause
+type Error struct {
+	Code    ErrorCode `json:"code,omitempty"`
+	Message string    `json:"message,omitempty"`
+	Details Details   `json:"details,omitempty"`
+	cause   error
+}
+
+func (e *Error) Error() string {
+	msg := string(e.Code)
+	if e.Message != "" {
+		msg += ": " + e.Message
+	}
+	// the message of an error converted by ErrorFrom is already the one of its cause
+	if e.cause != nil && e.cause.Error() != e.Message {
+		msg += ": " + e.cause.Error()
+	}
+	return msg
+}
+
+// MarshalXML writes the code and the message, details are only available in JSON and gRPC
+func (e *Error) MarshalXML(enc *xml.Encoder, start xml.StartElement) error {
+	start.Name = xml.Name{Local: "error"}
+	return enc.EncodeElement(struct {
+		Code    ErrorCode `xml:"code,omitempty"`
+		Message string    `xml:"message,omitempty"`
+	}{e.Code, e.Message}, start)
+}
+
+// Unwrap returns the wrapped cause
+func (e *Error) Unwrap() error {
+	return e.cause
+}
+
+// Is reports whether target is an *Error with the same code,
+// so errors.Is(err, &Error{Code: NotFound}) matches any NotFound error in the chain
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t != nil && e != nil && t.Code == e.Code
+}
+
+func NewError(code ErrorCode, m string) *Error {
+	return &Error{Code: code, Message: m}
+}
+
+func NewErrorf(code ErrorCode, f string, o ...interface{}) *Error {
+	return NewError(code, fmt.Sprintf(f, o...))
+}
+
+// Wrap returns an error with the given code wrapping cause
+func Wrap(cause error, code ErrorCode, m string) *Error {
+	return &Error{Code: code, Message: m, cause: cause}
+}
+
+func Wrapf(cause error, code ErrorCode, f string, o ...interface{}) *Error {
+	return Wrap(cause, code, fmt.Sprintf(f, o...))
+}
+
+// CodeOf returns the code of the first *Error or gRPC status in the chain of err, the code of a status
+// being read from its ErrorInfo detail as ErrorFrom does. Context errors are reported as DeadlineExceeded/Canceled, other errors as Unknown, nil as empty code
+func CodeOf(err error) ErrorCode {
+	if err == nil {
+		return ""
+	}
+	var (
+		e  *Error
+		gs grpcStatus
+	)
+	switch {
+	case errors.As(err, &e):
+		return e.Code
+	case errors.As(err, &gs):
+		if e := FromStatus(gs.GRPCStatus()); e != nil {
+			return e.Code
+		}
+		return Unknown
+	case errors.Is(err, context.DeadlineExceeded):
+		return DeadlineExceeded
+	case errors.Is(err, context.Canceled):
+		return Canceled
+	default:
+		return Unknown
+	}
+}
+
+// ErrorFrom returns the first *Error in the chain of err, converts a gRPC status error returned by a client
+// with FromStatus, or wraps err with the code given by CodeOf and its text as message
+func ErrorFrom(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	var (
+		e  *Error
+		gs grpcStatus
+	)
+	if errors.As(err, &e) {
+		return e
+	}
+	if errors.As(err, &gs) {
+		if e := FromStatus(gs.GRPCStatus()); e != nil {
+			return e
+		}
+	}
+	return Wrap(err, CodeOf(err), err.Error())
+}
+
+func NewCanceled(f string, o ...interface{}) *Error {
+	return NewErrorf(Canceled, f, o...)
+}
+
+func NewUnknown(f string, o ...interface{}) *Error {
+	return NewErrorf(Unknown, f, o...)
+}
+
+func NewInvalidArgument(f string, o ...interface{}) *Error {
+	return NewErrorf(InvalidArgument, f, o...)
+}
+
+func NewDeadlineExceeded(f string, o ...interface{}) *Error {
+	return NewErrorf(DeadlineExceeded, f, o...)
+}
+
+func NewNotFound(f string, o ...interface{}) *Error {
+	return NewErrorf(NotFound, f, o...)
+}
+
+func NewAlreadyExists(f string, o ...interface{}) *Error {
+	return NewErrorf(AlreadyExists, f, o...)
+}
+
+func NewPermissionDenied(f string, o ...interface{}) *Error {
+	return NewErrorf(PermissionDenied, f, o...)
+}
+
+func NewResourceExhausted(f string, o ...interface{}) *Error {
+	return NewErrorf(ResourceExhausted, f, o...)
+}
+
+func NewFailedPrecondition(f string, o ...interface{}) *Error {
+	return NewErrorf(FailedPrecondition, f, o...)
+}
+
+func NewAborted(f string, o ...interface{}) *Error {
+	return NewErrorf(Aborted, f, o...)
+}
+
+func NewOutOfRange(f string, o ...interface{}) *Error {
+	return NewErrorf(OutOfRange, f, o...)
+}
+
+func NewUnimplemented(f string, o ...interface{}) *Error {
+	return NewErrorf(Unimplemented, f, o...)
+}
+
+func NewInternal(f string, o ...interface{}) *Error {
+	return NewErrorf(Internal, f, o...)
+}
+
+func NewUnavailable(f string, o ...interface{}) *Error {
+	return NewErrorf(Unavailable, f, o...)
+}
+
+func NewDataLoss(f string, o ...interface{}) *Error {
+	return NewErrorf(DataLoss, f, o...)
+}
+
+func NewUnauthenticated(f string, o ...interface{}) *Error {
+	return NewErrorf(Unauthenticated, f, o...)
+}
+
+func NewMethodNotAllowed(f string, o ...interface{}) *Error {
+	return NewErrorf(MethodNotAllowed, f, o...)
+}
@@ -0,0 +1,302 @@
+package result
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/golang/protobuf/proto"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+const typeURLPrefix = "type.googleapis.com/"
+
+// Detail is a structured error detail modelled on the google.rpc error details,
+// serialised with an "@type" member in JSON and as a status detail in gRPC
+type Detail interface {
+	// TypeURL returns the type of the detail, e.g. "type.googleapis.com/google.rpc.BadRequest"
+	TypeURL() string
+	toProto() proto.Message
+}
+
+// Details list of error details, (un)marshaled as JSON objects discriminated by "@type"
+type Details []Detail
+
+// BadRequest describes violations in a client request
+type BadRequest struct {
+	FieldViolations []FieldViolation `json:"fieldViolations,omitempty"`
+}
+
+// FieldViolation describes a single bad request field,
+// Rule and Params are only serialised in JSON as google.rpc.BadRequest has no equivalent
+type FieldViolation struct {
+	// Field path, e.g. "address.street" or "items[0].name"
+	Field       string                 `json:"field"`
+	Description string                 `json:"description,omitempty"`
+	Rule        string                 `json:"rule,omitempty"`
+	Params      map[string]interface{} `json:"params,omitempty"`
+}
+
+// RetryInfo tells the client when it can retry the request
+type RetryInfo struct {
+	RetryDelay time.Duration `json:"retryDelay"`
+}
+
+// ResourceInfo describes the resource being accessed
+type ResourceInfo struct {
+	ResourceType string `json:"resourceType,omitempty"`
+	ResourceName string `json:"resourceName,omitempty"`
+	Owner        string `json:"owner,omitempty"`
+	Description  string `json:"description,omitempty"`
+}
+
+// PreconditionFailure describes what preconditions have failed
+type PreconditionFailure struct {
+	Violations []PreconditionViolation `json:"violations,omitempty"`
+}
+
+type PreconditionViolation struct {
+	Type        string `json:"type,omitempty"`
+	Subject     string `json:"subject,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// QuotaFailure describes how a quota check failed
+type QuotaFailure struct {
+	Violations []QuotaViolation `json:"violations,omitempty"`
+}
+
+type QuotaViolation struct {
+	Subject     string `json:"subject,omitempty"`
+	Description string `json:"description,omitempty"`
+}
+
+// ErrorInfo describes the cause of the error with a stable reason within a domain
+type ErrorInfo struct {
+	Reason   string            `json:"reason,omitempty"`
+	Domain   string            `json:"domain,omitempty"`
+	Metadata map[string]string `json:"metadata,omitempty"`
+}
+
+// RequestInfo identifies the request that failed, e.g. to correlate a client report with server logs
+type RequestInfo struct {
+	RequestID   string `json:"requestId,omitempty"`
+	ServingData string `json:"servingData,omitempty"`
+}
+
+var detailFactories = map[string]func() Detail{
+	(&BadRequest{}).TypeURL():          func() Detail { return &BadRequest{} },
+	(&RetryInfo{}).TypeURL():           func() Detail { return &RetryInfo{} },
+	(&ResourceInfo{}).TypeURL():        func() Detail { return &ResourceInfo{} },
+	(&PreconditionFailure{}).TypeURL(): func() Detail { return &PreconditionFailure{} },
+	(&QuotaFailure{}).TypeURL():        func() Detail { return &QuotaFailure{} },
+	(&ErrorInfo{}).TypeURL():           func() Detail { return &ErrorInfo{} },
+	(&RequestInfo{}).TypeURL():         func() Detail { return &RequestInfo{} },
+}
+
+// WithDetails appends details to the error and returns it
+func (e *Error) WithDetails(details ...Detail) *Error {
+	e.Details = append(e.Details, details...)
+	return e
+}
+
+func (*BadRequest) TypeURL() string          { return typeURLPrefix + "google.rpc.BadRequest" }
+func (*RetryInfo) TypeURL() string           { return typeURLPrefix + "google.rpc.RetryInfo" }
+func (*ResourceInfo) TypeURL() string        { return typeURLPrefix + "google.rpc.ResourceInfo" }
+func (*PreconditionFailure) TypeURL() string { return typeURLPrefix + "google.rpc.PreconditionFailure" }
+func (*QuotaFailure) TypeURL() string        { return typeURLPrefix + "google.rpc.QuotaFailure" }
+func (*ErrorInfo) TypeURL() string           { return typeURLPrefix + "google.rpc.ErrorInfo" }
+func (*RequestInfo) TypeURL() string         { return typeURLPrefix + "google.rpc.RequestInfo" }
+
+func (d *BadRequest) toProto() proto.Message {
+	p := &errdetails.BadRequest{}
+	for _, v := range d.FieldViolations {
+		p.FieldViolations = append(p.FieldViolations, &errdetails.BadRequest_FieldViolation{
+			Field:       v.Field,
+			Description: v.Description,
+		})
+	}
+	return p
+}
+
+func (d *RetryInfo) toProto() proto.Message {
+	return &errdetails.RetryInfo{RetryDelay: durationpb.New(d.RetryDelay)}
+}
+
+func (d *ResourceInfo) toProto() proto.Message {
+	return &errdetails.ResourceInfo{
+		ResourceType: d.ResourceType,
+		ResourceName: d.ResourceName,
+		Owner:        d.Owner,
+		Description:  d.Description,
+	}
+}
+
+func (d *PreconditionFailure) toProto() proto.Message {
+	p := &errdetails.PreconditionFailure{}
+	for _, v := range d.Violations {
+		p.Violations = append(p.Violations, &errdetails.PreconditionFailure_Violation{
+			Type:        v.Type,
+			Subject:     v.Subject,
+			Description: v.Description,
+		})
+	}
+	return p
+}
+
+func (d *QuotaFailure) toProto() proto.Message {
+	p := &errdetails.QuotaFailure{}
+	for _, v := range d.Violations {
+		p.Violations = append(p.Violations, &errdetails.QuotaFailure_Violation{
+			Subject:     v.Subject,
+			Description: v.Description,
+		})
+	}
+	return p
+}
+
+func (d *ErrorInfo) toProto() proto.Message {
+	return &errdetails.ErrorInfo{
+		Reason:   d.Reason,
+		Domain:   d.Domain,
+		Metadata: d.Metadata,
+	}
+}
+
+func (d *RequestInfo) toProto() proto.Message {
+	return &errdetails.RequestInfo{
+		RequestId:   d.RequestID,
+		ServingData: d.ServingData,
+	}
+}
+
+// MarshalJSON writes the delay in the protobuf JSON duration format, e.g. "1.5s"
+func (d *RetryInfo) MarshalJSON() ([]byte, error) {
+	return json.Marshal(map[string]string{
+		"retryDelay": strconv.FormatFloat(d.RetryDelay.Seconds(), 'f', -1, 64) + "s",
+	})
+}
+
+func (d *RetryInfo) UnmarshalJSON(b []byte) error {
+	var v struct {
+		RetryDelay string `json:"retryDelay"`
+	}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return err
+	}
+	if v.RetryDelay == "" {
+		d.RetryDelay = 0
+		return nil
+	}
+	delay, err := time.ParseDuration(v.RetryDelay)
+	if err != nil {
+		return fmt.Errorf("invalid retryDelay %q: %w", v.RetryDelay, err)
+	}
+	d.RetryDelay = delay
+	return nil
+}
+
+func (d Details) MarshalJSON() ([]byte, error) {
+	items := make([]map[string]json.RawMessage, 0, len(d))
+	for _, detail := range d {
+		b, err := json.Marshal(detail)
+		if err != nil {
+			return nil, err
+		}
+		item := make(map[string]json.RawMessage)
+		if err := json.Unmarshal(b, &item); err != nil {
+			return nil, err
+		}
+		typeURL, _ := json.Marshal(detail.TypeURL())
+		item["@type"] = typeURL
+		items = append(items, item)
+	}
+	return json.Marshal(items)
+}
+
+// UnmarshalJSON reads details by their "@type", unknown types are skipped
+func (d *Details) UnmarshalJSON(b []byte) error {
+	var items []json.RawMessage
+	if err := json.Unmarshal(b, &items); err != nil {
+		return err
+	}
+	details := make(Details, 0, len(items))
+	for _, item := range items {
+		var typed struct {
+			Type string `json:"@type"`
+		}
+		if err := json.Unmarshal(item, &typed); err != nil {
+			return err
+		}
+		factory, ok := detailFactories[typed.Type]
+		if !ok {
+			continue
+		}
+		detail := factory()
+		if err := json.Unmarshal(item, detail); err != nil {
+			return fmt.Errorf("invalid detail %s: %w", strings.TrimPrefix(typed.Type, typeURLPrefix), err)
+		}
+		details = append(details, detail)
+	}
+	*d = details
+	return nil
+}
+
+// detailFromProto converts a google.rpc error detail, nil for unsupported messages
+func detailFromProto(m interface{}) Detail {
+	switch p := m.(type) {
+	case *errdetails.BadRequest:
+		d := &BadRequest{}
+		for _, v := range p.GetFieldViolations() {
+			d.FieldViolations = append(d.FieldViolations, FieldViolation{
+				Field:       v.GetField(),
+				Description: v.GetDescription(),
+			})
+		}
+		return d
+	case *errdetails.RetryInfo:
+		return &RetryInfo{RetryDelay: p.GetRetryDelay().AsDuration()}
+	case *errdetails.ResourceInfo:
+		return &ResourceInfo{
+			ResourceType: p.GetResourceType(),
+			ResourceName: p.GetResourceName(),
+			Owner:        p.GetOwner(),
+			Description:  p.GetDescription(),
+		}
+	case *errdetails.PreconditionFailure:
+		d := &PreconditionFailure{}
+		for _, v := range p.GetViolations() {
+			d.Violations = append(d.Violations, PreconditionViolation{
+				Type:        v.GetType(),
+				Subject:     v.GetSubject(),
+				Description: v.GetDescription(),
+			})
+		}
+		return d
+	case *errdetails.QuotaFailure:
+		d := &QuotaFailure{}
+		for _, v := range p.GetViolations() {
+			d.Violations = append(d.Violations, QuotaViolation{
+				Subject:     v.GetSubject(),
+				Description: v.GetDescription(),
+			})
+		}
+		return d
+	case *errdetails.ErrorInfo:
+		return &ErrorInfo{
+			Reason:   p.GetReason(),
+			Domain:   p.GetDomain(),
+			Metadata: p.GetMetadata(),
+		}
+	case *errdetails.RequestInfo:
+		return &RequestInfo{
+			RequestID:   p.GetRequestId(),
+			ServingData: p.GetServingData(),
+		}
+	default:
+		return nil
+	}
+}
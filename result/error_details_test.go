@@ -0,0 +1,125 @@
+package result
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/go-openapi/runtime"
+	"github.com/jedrp/go-core/log"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorDetailsJSON(t *testing.T) {
+	err := NewInvalidArgument("invalid user").WithDetails(
+		&BadRequest{FieldViolations: []FieldViolation{{Field: "email", Description: "required"}}},
+		&RetryInfo{RetryDelay: 1500 * time.Millisecond},
+	)
+	b, e := json.Marshal(err)
+	if e != nil {
+		t.Fatal(e)
+	}
+	expected := `{"code":"InvalidArgument","message":"invalid user","details":[` +
+		`{"@type":"type.googleapis.com/google.rpc.BadRequest","fieldViolations":[{"field":"email","description":"required"}]},` +
+		`{"@type":"type.googleapis.com/google.rpc.RetryInfo","retryDelay":"1.5s"}]}`
+	if string(b) != expected {
+		t.Errorf("expected %s but got %s", expected, b)
+	}
+
+	var decoded Error
+	if e := json.Unmarshal(b, &decoded); e != nil {
+		t.Fatal(e)
+	}
+	if len(decoded.Details) != 2 {
+		t.Fatalf("expected 2 details but got %d", len(decoded.Details))
+	}
+	if br, ok := decoded.Details[0].(*BadRequest); !ok || br.FieldViolations[0].Field != "email" {
+		t.Errorf("unexpected detail %#v", decoded.Details[0])
+	}
+	if ri, ok := decoded.Details[1].(*RetryInfo); !ok || ri.RetryDelay != 1500*time.Millisecond {
+		t.Errorf("unexpected detail %#v", decoded.Details[1])
+	}
+}
+
+func TestErrorDetailsRPC(t *testing.T) {
+	err := NewNotFound("missing").WithDetails(&ResourceInfo{ResourceType: "user", ResourceName: "42"})
+	st, _ := status.FromError(GetRPCError(err))
+	details := st.Details()
+	if len(details) != 2 {
+		t.Fatalf("expected the code and resource info details but got %d", len(details))
+	}
+	if ei, ok := details[0].(*errdetails.ErrorInfo); !ok || ei.Reason != "NotFound" || ei.Domain != ErrorCodeDomain {
+		t.Errorf("unexpected detail %#v", details[0])
+	}
+	if ri, ok := details[1].(*errdetails.ResourceInfo); !ok || ri.ResourceName != "42" {
+		t.Errorf("unexpected detail %#v", details[1])
+	}
+}
+
+func TestErrorFromRPCRoundTrip(t *testing.T) {
+	sent := NewFailedPrecondition("order already shipped").WithDetails(
+		&PreconditionFailure{Violations: []PreconditionViolation{{Type: "STATE", Subject: "order/1", Description: "shipped"}}},
+	)
+	err := fmt.Errorf("calling orders: %w", GetRPCError(sent))
+
+	if code := CodeOf(err); code != FailedPrecondition {
+		t.Errorf("expected FailedPrecondition but got %v", code)
+	}
+	received := ErrorFrom(err)
+	if received.Code != FailedPrecondition || received.Message != sent.Message {
+		t.Errorf("expected %v but got %v", sent, received)
+	}
+	if pf, ok := received.Details[0].(*PreconditionFailure); !ok || pf.Violations[0].Subject != "order/1" {
+		t.Errorf("unexpected detail %#v", received.Details[0])
+	}
+}
+
+func TestRPCErrorKeepsCodeAndRequestID(t *testing.T) {
+	const customCode ErrorCode = "QuotaExceeded"
+	ctx := context.WithValue(context.Background(), log.RequestID, "req-1")
+	st, _ := status.FromError(GetRPCErrorWithContext(ctx, NewError(customCode, "100%s used")))
+	if st.Message() != "100%s used" {
+		t.Errorf("expected message not to be formatted but got %q", st.Message())
+	}
+
+	received := FromStatus(st)
+	if received.Code != customCode {
+		t.Errorf("expected %v but got %v", customCode, received.Code)
+	}
+	if len(received.Details) != 1 {
+		t.Fatalf("expected only the request info detail but got %d", len(received.Details))
+	}
+	if ri, ok := received.Details[0].(*RequestInfo); !ok || ri.RequestID != "req-1" {
+		t.Errorf("unexpected detail %#v", received.Details[0])
+	}
+}
+
+func TestErrorFromHTTPResponse(t *testing.T) {
+	sent := NewAlreadyExists("user exists").WithDetails(&ErrorInfo{Reason: "USER_EXISTS", Domain: "users"})
+
+	for _, problem := range []bool{false, true} {
+		ConfigureProblemDetails(problem, "")
+		rw := httptest.NewRecorder()
+		FailWith(sent).WriteResponse(rw, runtime.JSONProducer())
+		received := FromHTTPResponse(rw.Result())
+		if received.Code != AlreadyExists || received.Message != "user exists" {
+			t.Errorf("problem %v: expected %v but got %v", problem, sent, received)
+		}
+		if len(received.Details) != 1 {
+			t.Errorf("problem %v: expected 1 detail but got %d", problem, len(received.Details))
+		}
+	}
+	ConfigureProblemDetails(false, "")
+
+	resp := &http.Response{StatusCode: 503, Header: http.Header{"Content-Type": {"text/plain"}}, Body: io.NopCloser(strings.NewReader("down"))}
+	if e := FromHTTPResponse(resp); e.Code != Unavailable {
+		t.Errorf("expected Unavailable but got %v", e.Code)
+	}
+}
@@ -0,0 +1,92 @@
+package result
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestErrorWrapping(t *testing.T) {
+	cause := errors.New("connection refused")
+	err := fmt.Errorf("loading user: %w", Wrap(cause, Unavailable, "user store"))
+
+	if !errors.Is(err, cause) {
+		t.Error("expected cause to be found in the chain")
+	}
+	if !errors.Is(err, &Error{Code: Unavailable}) {
+		t.Error("expected errors.Is to match by code")
+	}
+	if errors.Is(err, &Error{Code: NotFound}) {
+		t.Error("expected errors.Is not to match another code")
+	}
+	if err.Error() != "loading user: Unavailable: user store: connection refused" {
+		t.Errorf("unexpected message %q", err.Error())
+	}
+}
+
+func TestCodeOf(t *testing.T) {
+	tt := []struct {
+		err      error
+		expected ErrorCode
+	}{
+		{err: nil, expected: ""},
+		{err: NewNotFound("user %d", 1), expected: NotFound},
+		{err: fmt.Errorf("wrapped: %w", NewAborted("conflict")), expected: Aborted},
+		{err: fmt.Errorf("wrapped: %w", context.DeadlineExceeded), expected: DeadlineExceeded},
+		{err: errors.New("plain"), expected: Unknown},
+		{err: status.Error(codes.NotFound, "missing"), expected: NotFound},
+		// the ErrorInfo detail keeps the codes sharing a gRPC code apart
+		{err: fmt.Errorf("wrapped: %w", GetRPCError(NewMethodNotAllowed("POST"))), expected: MethodNotAllowed},
+	}
+	for i, tc := range tt {
+		if code := CodeOf(tc.err); code != tc.expected {
+			t.Errorf("tc #%d, expected %v but got %v", i, tc.expected, code)
+		}
+	}
+
+	r := FailWith(fmt.Errorf("wrapped: %w", NewPermissionDenied("nope")))
+	if r.Error.Code != PermissionDenied {
+		t.Errorf("expected PermissionDenied but got %v", r.Error.Code)
+	}
+}
+
+func TestErrorFrom(t *testing.T) {
+	notFound := NewNotFound("user %d", 1)
+	tt := []struct {
+		err      error
+		code     ErrorCode
+		message  string
+		expected string
+	}{
+		{err: errors.New("plain"), code: Unknown, message: "plain", expected: "Unknown: plain"},
+		{err: fmt.Errorf("query: %w", context.Canceled), code: Canceled, message: "query: context canceled", expected: "Canceled: query: context canceled"},
+		{err: fmt.Errorf("wrapped: %w", notFound), code: NotFound, message: "user 1", expected: "NotFound: user 1"},
+	}
+	for i, tc := range tt {
+		e := ErrorFrom(tc.err)
+		if e.Code != tc.code || e.Message != tc.message || e.Error() != tc.expected {
+			t.Errorf("#%d: expected %s %q %q but got %s %q %q", i, tc.code, tc.message, tc.expected, e.Code, e.Message, e.Error())
+		}
+		if !errors.Is(e, tc.err) && !errors.Is(tc.err, e) {
+			t.Errorf("#%d: expected the error to be kept in the chain", i)
+		}
+	}
+	if ErrorFrom(nil) != nil {
+		t.Error("expected nil for a nil error")
+	}
+}
+
+func TestErrorIsTypedNil(t *testing.T) {
+	var target *Error
+	if errors.Is(NewNotFound("user"), target) {
+		t.Error("expected a typed nil target not to match")
+	}
+	var e *Error
+	if e.Is(&Error{Code: NotFound}) {
+		t.Error("expected a nil error not to match")
+	}
+}
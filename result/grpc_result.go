@@ -1,51 +1,96 @@
 package result
 
 import (
+	"context"
 	"errors"
 
+	"github.com/golang/protobuf/proto"
+	"github.com/jedrp/go-core/log/logctx"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 )
 
+// ErrorCodeDomain is the domain of the google.rpc.ErrorInfo detail carrying the ErrorCode in gRPC statuses
+const ErrorCodeDomain = "github.com/jedrp/go-core/result"
+
+var grpcCodeMappings = map[codes.Code]ErrorCode{
+	codes.Canceled:           Canceled,
+	codes.Unknown:            Unknown,
+	codes.InvalidArgument:    InvalidArgument,
+	codes.DeadlineExceeded:   DeadlineExceeded,
+	codes.NotFound:           NotFound,
+	codes.AlreadyExists:      AlreadyExists,
+	codes.PermissionDenied:   PermissionDenied,
+	codes.ResourceExhausted:  ResourceExhausted,
+	codes.FailedPrecondition: FailedPrecondition,
+	codes.Aborted:            Aborted,
+	codes.OutOfRange:         OutOfRange,
+	codes.Unimplemented:      Unimplemented,
+	codes.Internal:           Internal,
+	codes.Unavailable:        Unavailable,
+	codes.DataLoss:           DataLoss,
+	codes.Unauthenticated:    Unauthenticated,
+}
+
+// GetRPCError converts err to a gRPC status error. The ErrorCode is attached as a google.rpc.ErrorInfo
+// of the ErrorCodeDomain and the details as google.rpc status details, so FromStatus restores the same Error.
 func GetRPCError(err *Error) error {
+	return GetRPCErrorWithContext(context.Background(), err)
+}
+
+// GetRPCErrorWithContext is GetRPCError also attaching the request ID of ctx as google.rpc.RequestInfo
+func GetRPCErrorWithContext(ctx context.Context, err *Error) error {
 	if err == nil {
 		return errors.New("unknown error")
 	}
-	var grpcCode codes.Code
-	switch err.Code {
-	case Aborted:
-		grpcCode = codes.Aborted
-	case ResourceExhausted:
-		grpcCode = codes.ResourceExhausted
-	case AlreadyExists:
-		grpcCode = codes.AlreadyExists
-	case Canceled:
-		grpcCode = codes.Canceled
-	case DataLoss:
-		grpcCode = codes.DataLoss
-	case DeadlineExceeded:
-		grpcCode = codes.DeadlineExceeded
-	case FailedPrecondition:
-		grpcCode = codes.FailedPrecondition
-	case Internal:
-		grpcCode = codes.Internal
-	case InvalidArgument:
-		grpcCode = codes.InvalidArgument
-	case NotFound:
-		grpcCode = codes.NotFound
-	case OutOfRange:
-		grpcCode = codes.OutOfRange
-	case PermissionDenied:
-		grpcCode = codes.PermissionDenied
-	case Unauthenticated:
-		grpcCode = codes.Unauthenticated
-	case Unavailable:
-		grpcCode = codes.Unavailable
-	case Unimplemented:
-		grpcCode = codes.Unimplemented
-	default:
-		grpcCode = codes.Unknown
-	}
-
-	return status.Errorf(grpcCode, err.Message)
+	details := make([]proto.Message, 0, len(err.Details)+2)
+	details = append(details, (&ErrorInfo{Reason: string(err.Code), Domain: ErrorCodeDomain}).toProto())
+	hasRequestInfo := false
+	for _, d := range err.Details {
+		if _, ok := d.(*RequestInfo); ok {
+			hasRequestInfo = true
+		}
+		details = append(details, d.toProto())
+	}
+	if requestID := logctx.StringField(ctx, logctx.RequestID); requestID != "" && !hasRequestInfo {
+		details = append(details, (&RequestInfo{RequestID: requestID}).toProto())
+	}
+
+	st := status.New(GRPCCode(err.Code), err.Message)
+	if withDetails, e := st.WithDetails(details...); e == nil {
+		st = withDetails
+	}
+	return st.Err()
+}
+
+// CodeFromGRPC returns the ErrorCode of a gRPC code, Unknown for codes without equivalent
+func CodeFromGRPC(c codes.Code) ErrorCode {
+	if code, found := grpcCodeMappings[c]; found {
+		return code
+	}
+	return Unknown
+}
+
+// FromStatus converts a gRPC status and its google.rpc details to an Error, nil for an OK status.
+// The code is the one attached by GetRPCError when present, otherwise it is derived from the gRPC code.
+func FromStatus(st *status.Status) *Error {
+	if st == nil || st.Code() == codes.OK {
+		return nil
+	}
+	e := &Error{
+		Code:    CodeFromGRPC(st.Code()),
+		Message: st.Message(),
+	}
+	for _, d := range st.Details() {
+		detail := detailFromProto(d)
+		if detail == nil {
+			continue
+		}
+		if info, ok := detail.(*ErrorInfo); ok && info.Domain == ErrorCodeDomain {
+			e.Code = ErrorCode(info.Reason)
+			continue
+		}
+		e.Details = append(e.Details, detail)
+	}
+	return e
 }
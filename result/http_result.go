@@ -0,0 +1,86 @@
+package result
+
+import (
+	"encoding/json"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+)
+
+// maxErrorBodySize limits how much of an error response body is read
+const maxErrorBodySize = 1 << 20
+
+var httpStatusMappings = map[int]ErrorCode{
+	http.StatusBadRequest:                   InvalidArgument,
+	http.StatusUnauthorized:                 Unauthenticated,
+	http.StatusForbidden:                    PermissionDenied,
+	http.StatusNotFound:                     NotFound,
+	http.StatusMethodNotAllowed:             MethodNotAllowed,
+	http.StatusConflict:                     Aborted,
+	http.StatusPreconditionFailed:           FailedPrecondition,
+	http.StatusRequestedRangeNotSatisfiable: OutOfRange,
+	http.StatusTooManyRequests:              ResourceExhausted,
+	499:                                     Canceled,
+	http.StatusInternalServerError:          Internal,
+	http.StatusNotImplemented:               Unimplemented,
+	http.StatusServiceUnavailable:           Unavailable,
+	http.StatusGatewayTimeout:               DeadlineExceeded,
+}
+
+// CodeFromHTTPStatus returns the ErrorCode of an HTTP error status,
+// other 4xx are reported as FailedPrecondition and other statuses as Unknown
+func CodeFromHTTPStatus(httpStatus int) ErrorCode {
+	if code, found := httpStatusMappings[httpStatus]; found {
+		return code
+	}
+	if httpStatus >= 400 && httpStatus < 500 {
+		return FailedPrecondition
+	}
+	return Unknown
+}
+
+// FromHTTPResponse reads an error response written by WriteResponse, either {code, message, details}
+// or problem+json, back into an Error. It returns nil for a non error status.
+// The code falls back to the HTTP status when the body does not carry one.
+func FromHTTPResponse(resp *http.Response) *Error {
+	if resp == nil || resp.StatusCode < 400 {
+		return nil
+	}
+	e := &Error{}
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodySize))
+	if err == nil && len(body) > 0 && isJSON(resp.Header.Get("Content-Type")) {
+		var payload struct {
+			Code    ErrorCode `json:"code"`
+			Message string    `json:"message"`
+			Detail  string    `json:"detail"`
+			Details Details   `json:"details"`
+		}
+		if json.Unmarshal(body, &payload) == nil {
+			e.Code = payload.Code
+			e.Message = payload.Message
+			if e.Message == "" {
+				e.Message = payload.Detail
+			}
+			e.Details = payload.Details
+		}
+	}
+	if e.Code == "" {
+		e.Code = CodeFromHTTPStatus(resp.StatusCode)
+	}
+	if e.Message == "" {
+		e.Message = http.StatusText(resp.StatusCode)
+	}
+	return e
+}
+
+func isJSON(contentType string) bool {
+	if contentType == "" {
+		return true
+	}
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	return mediaType == "application/json" || strings.HasSuffix(mediaType, "+json")
+}
@@ -0,0 +1,453 @@
+package result
+
+import (
+	"bytes"
+	"context"
+	"encoding/csv"
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"reflect"
+	"strconv"
+	"strings"
+	"sync"
+
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Media types supported out of the box by Write
+const (
+	JSONMediaType = "application/json"
+	XMLMediaType  = "application/xml"
+	CSVMediaType  = "text/csv"
+)
+
+// Encoder writes v to w in a given media type
+type Encoder func(w io.Writer, v interface{}) error
+
+// CSVMarshaler is implemented by values rendering themselves as CSV records, header included
+type CSVMarshaler interface {
+	MarshalCSV() ([][]string, error)
+}
+
+type encoderEntry struct {
+	mediaType string
+	encoder   Encoder
+}
+
+var (
+	encodersMux sync.RWMutex
+	// in order of preference when the client accepts several media types with the same quality
+	encoders = []encoderEntry{
+		{JSONMediaType, EncodeJSON},
+		{XMLMediaType, EncodeXML},
+		{CSVMediaType, EncodeCSV},
+	}
+)
+
+// RegisterEncoder adds or replaces the encoder of a media type used by Write
+func RegisterEncoder(mediaType string, encoder Encoder) {
+	encodersMux.Lock()
+	defer encodersMux.Unlock()
+	for i, e := range encoders {
+		if e.mediaType == mediaType {
+			encoders[i].encoder = encoder
+			return
+		}
+	}
+	encoders = append(encoders, encoderEntry{mediaType, encoder})
+}
+
+// Created returns a 201 result with the Location header
+func Created(v interface{}, location string) *Result {
+	return OK(v).WithStatus(http.StatusCreated).WithHeader("Location", location)
+}
+
+// Accepted returns a 202 result
+func Accepted(v interface{}) *Result {
+	return OK(v).WithStatus(http.StatusAccepted)
+}
+
+// NoContent returns a 204 result, written without body
+func NoContent() *Result {
+	return OK(nil).WithStatus(http.StatusNoContent)
+}
+
+// WithStatus sets the status of a success response, 200 by default
+func (r *Result) WithStatus(status int) *Result {
+	r.status = status
+	return r
+}
+
+// WithETag sets the ETag header, the tag is quoted when it is not already
+func (r *Result) WithETag(etag string) *Result {
+	if !strings.HasSuffix(etag, `"`) {
+		etag = `"` + etag + `"`
+	}
+	if r.headers == nil {
+		r.headers = make(http.Header)
+	}
+	r.headers.Set("ETag", etag)
+	return r
+}
+
+// Write writes the result to a plain net/http response, negotiating the media type from the Accept header of req.
+// Only the media types able to encode the value are offered, JSON being the fallback when the client accepts none of them,
+// unless it refuses JSON explicitly, e.g. "application/json;q=0", which is answered with 406 Not Acceptable.
+// Errors are written as problem+json when enabled with ConfigureProblemDetails, JSON or XML.
+// When the value cannot be encoded a 500 error is written and the encoding error returned.
+func (r *Result) Write(rw http.ResponseWriter, req *http.Request) error {
+	ctx := r.ctx
+	if ctx == nil {
+		ctx = req.Context()
+	}
+	r.writeHeaders(rw)
+	rw.Header().Add("Vary", "Accept")
+
+	accept := req.Header.Get("Accept")
+	if r.Error != nil {
+		return writeError(ctx, rw, accept, r.Error)
+	}
+
+	status := r.statusCode()
+	if status == http.StatusNoContent || status == http.StatusNotModified {
+		rw.WriteHeader(status)
+		return nil
+	}
+	encodersMux.RLock()
+	offers := make([]string, 0, len(encoders))
+	encoderOf := make(map[string]Encoder, len(encoders))
+	for _, e := range encoders {
+		if canEncode(e.mediaType, r.Value) {
+			offers = append(offers, e.mediaType)
+			encoderOf[e.mediaType] = e.encoder
+		}
+	}
+	encodersMux.RUnlock()
+	mediaType, ok := negotiate(accept, offers)
+	if !ok {
+		if refuses(accept, JSONMediaType) {
+			http.Error(rw, "none of the accepted media types can encode the response, available: "+strings.Join(offers, ", "), http.StatusNotAcceptable)
+			return nil
+		}
+		mediaType = JSONMediaType
+	}
+	encoder := encoderOf[mediaType]
+	if encoder == nil {
+		encoder = EncodeJSON
+	}
+	if err := writeBody(rw, status, mediaType, encoder, r.Value); err != nil {
+		writeError(ctx, rw, "", NewInternal("cannot encode the response as %s", mediaType))
+		return err
+	}
+	return nil
+}
+
+func writeError(ctx context.Context, rw http.ResponseWriter, accept string, e *Error) error {
+	offers := []string{JSONMediaType, XMLMediaType}
+	if problemSetting.enabled {
+		offers = append([]string{ProblemMediaType}, offers...)
+	}
+	var body interface{} = e
+	// errors are always written, in JSON when the client accepts none of the offers
+	mediaType, _ := negotiate(accept, offers)
+	switch mediaType {
+	case ProblemMediaType:
+		body = NewProblem(ctx, e)
+	case JSONMediaType, "":
+		mediaType = JSONMediaType
+		if problemSetting.enabled {
+			body = NewProblem(ctx, e)
+			mediaType = ProblemMediaType
+		}
+	}
+	encoder := EncodeJSON
+	if mediaType == XMLMediaType {
+		encoder = EncodeXML
+	}
+	return writeBody(rw, HTTPStatus(e.Code), mediaType, encoder, body)
+}
+
+// HandlerFunc adapts a function returning a Result to a net/http handler.
+// The errors of Write are dropped, the client being sent a 500, see Handler to report them.
+type HandlerFunc func(*http.Request) *Result
+
+func (f HandlerFunc) ServeHTTP(rw http.ResponseWriter, req *http.Request) {
+	f(req).Write(rw, req)
+}
+
+// Handler adapts f to a net/http handler reporting the errors of Write to onError, e.g. to log them
+func Handler(f HandlerFunc, onError func(*http.Request, error)) http.Handler {
+	return http.HandlerFunc(func(rw http.ResponseWriter, req *http.Request) {
+		if err := f(req).Write(rw, req); err != nil && onError != nil {
+			onError(req, err)
+		}
+	})
+}
+
+func (r *Result) statusCode() int {
+	if r.status == 0 {
+		return http.StatusOK
+	}
+	return r.status
+}
+
+func (r *Result) writeHeaders(rw http.ResponseWriter) {
+	for k, values := range r.headers {
+		for _, v := range values {
+			rw.Header().Add(k, v)
+		}
+	}
+}
+
+// writeBody encodes before writing the status, so nothing is written when the encoding fails
+func writeBody(rw http.ResponseWriter, status int, mediaType string, encoder Encoder, v interface{}) error {
+	var b bytes.Buffer
+	if err := encoder(&b, v); err != nil {
+		return err
+	}
+	rw.Header().Set("Content-Type", mediaType)
+	rw.WriteHeader(status)
+	_, err := rw.Write(b.Bytes())
+	return err
+}
+
+// canEncode reports whether the built-in encoder of the media type supports the type of v,
+// registered media types are assumed to encode any value
+func canEncode(mediaType string, v interface{}) bool {
+	switch mediaType {
+	case XMLMediaType:
+		return v == nil || xmlEncodable(reflect.TypeOf(v), make(map[reflect.Type]bool))
+	case CSVMediaType:
+		switch v.(type) {
+		case CSVMarshaler, [][]string:
+			return true
+		}
+		kind := reflect.Indirect(reflect.ValueOf(v)).Kind()
+		return kind == reflect.Slice || kind == reflect.Array
+	default:
+		return true
+	}
+}
+
+var xmlMarshalerType = reflect.TypeOf((*xml.Marshaler)(nil)).Elem()
+
+// xmlEncodable reports whether encoding/xml supports t, maps, channels and functions are not
+func xmlEncodable(t reflect.Type, visited map[reflect.Type]bool) bool {
+	if visited[t] || t.Implements(xmlMarshalerType) || reflect.PtrTo(t).Implements(xmlMarshalerType) {
+		return true
+	}
+	visited[t] = true
+	switch t.Kind() {
+	case reflect.Map, reflect.Chan, reflect.Func, reflect.Complex64, reflect.Complex128, reflect.UnsafePointer:
+		return false
+	case reflect.Ptr, reflect.Slice, reflect.Array:
+		return xmlEncodable(t.Elem(), visited)
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			if !f.IsExported() || f.Tag.Get("xml") == "-" {
+				continue
+			}
+			if !xmlEncodable(f.Type, visited) {
+				return false
+			}
+		}
+	}
+	return true
+}
+
+type acceptRange struct {
+	mediaType string
+	quality   float64
+}
+
+func parseAccept(accept string) []acceptRange {
+	var ranges []acceptRange
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		quality := 1.0
+		if q, found := params["q"]; found {
+			if quality, err = strconv.ParseFloat(q, 64); err != nil {
+				continue
+			}
+		}
+		ranges = append(ranges, acceptRange{mediaType, quality})
+	}
+	return ranges
+}
+
+// quality returns the quality of the most specific range matching the media type, false when none matches
+func quality(ranges []acceptRange, mediaType string) (float64, bool) {
+	specificity, q := -1, 0.0
+	for _, r := range ranges {
+		if !matchMediaType(r.mediaType, mediaType) {
+			continue
+		}
+		// more specific ranges override the wildcards
+		s := 2 - strings.Count(r.mediaType, "*")
+		if s > specificity {
+			specificity, q = s, r.quality
+		}
+	}
+	return q, specificity >= 0
+}
+
+// negotiate returns the offer of highest quality, ties resolved by the order of the offers,
+// false when the Accept header accepts none of them. Any offer is accepted without Accept header.
+func negotiate(accept string, offers []string) (string, bool) {
+	ranges := parseAccept(accept)
+	if len(ranges) == 0 {
+		if len(offers) == 0 {
+			return "", false
+		}
+		return offers[0], true
+	}
+	best, bestQuality := "", 0.0
+	for _, offer := range offers {
+		if q, _ := quality(ranges, offer); q > bestQuality {
+			best, bestQuality = offer, q
+		}
+	}
+	return best, best != ""
+}
+
+// refuses reports whether the Accept header explicitly gives the media type a zero quality
+func refuses(accept string, mediaType string) bool {
+	q, found := quality(parseAccept(accept), mediaType)
+	return found && q == 0
+}
+
+func matchMediaType(pattern, mediaType string) bool {
+	if pattern == "*/*" || pattern == mediaType {
+		return true
+	}
+	if strings.HasSuffix(pattern, "/*") {
+		return strings.HasPrefix(mediaType, strings.TrimSuffix(pattern, "*"))
+	}
+	return false
+}
+
+// EncodeJSON writes v as JSON, protobuf messages are written with the protobuf JSON mapping
+func EncodeJSON(w io.Writer, v interface{}) error {
+	if m, ok := v.(protoreflect.ProtoMessage); ok {
+		b, err := protojson.Marshal(m)
+		if err != nil {
+			return err
+		}
+		_, err = w.Write(b)
+		return err
+	}
+	return json.NewEncoder(w).Encode(v)
+}
+
+// EncodeXML writes v as XML, the items of a slice are wrapped in an <items> root element
+func EncodeXML(w io.Writer, v interface{}) error {
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	enc := xml.NewEncoder(w)
+	if kind := reflect.ValueOf(v).Kind(); kind != reflect.Slice && kind != reflect.Array || reflect.TypeOf(v).Implements(xmlMarshalerType) {
+		return enc.Encode(v)
+	}
+	root := xml.StartElement{Name: xml.Name{Local: "items"}}
+	if err := enc.EncodeToken(root); err != nil {
+		return err
+	}
+	if err := enc.Encode(v); err != nil {
+		return err
+	}
+	if err := enc.EncodeToken(root.End()); err != nil {
+		return err
+	}
+	return enc.Flush()
+}
+
+// EncodeCSV writes v as CSV, v is a CSVMarshaler, [][]string or a slice of structs
+// whose header is given by the csv tags or the field names
+func EncodeCSV(w io.Writer, v interface{}) error {
+	var records [][]string
+	switch value := v.(type) {
+	case CSVMarshaler:
+		var err error
+		if records, err = value.MarshalCSV(); err != nil {
+			return err
+		}
+	case [][]string:
+		records = value
+	default:
+		var err error
+		if records, err = csvRecords(v); err != nil {
+			return err
+		}
+	}
+	cw := csv.NewWriter(w)
+	if err := cw.WriteAll(records); err != nil {
+		return err
+	}
+	return cw.Error()
+}
+
+// MarshalCSV writes the items of the page
+func (p Page[T]) MarshalCSV() ([][]string, error) {
+	return csvRecords(p.Items)
+}
+
+// MarshalCSV writes the items of the page
+func (p CursorPage[T]) MarshalCSV() ([][]string, error) {
+	return csvRecords(p.Items)
+}
+
+func csvRecords(v interface{}) ([][]string, error) {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Slice && rv.Kind() != reflect.Array {
+		return nil, fmt.Errorf("csv: unsupported value of type %T", v)
+	}
+	elemType := rv.Type().Elem()
+	for elemType.Kind() == reflect.Ptr {
+		elemType = elemType.Elem()
+	}
+	if elemType.Kind() != reflect.Struct {
+		records := make([][]string, 0, rv.Len())
+		for i := 0; i < rv.Len(); i++ {
+			records = append(records, []string{fmt.Sprint(rv.Index(i).Interface())})
+		}
+		return records, nil
+	}
+
+	var (
+		header []string
+		fields []int
+	)
+	for i := 0; i < elemType.NumField(); i++ {
+		f := elemType.Field(i)
+		name := f.Tag.Get("csv")
+		if !f.IsExported() || name == "-" {
+			continue
+		}
+		if name == "" {
+			name = f.Name
+		}
+		header = append(header, name)
+		fields = append(fields, i)
+	}
+	records := [][]string{header}
+	for i := 0; i < rv.Len(); i++ {
+		item := reflect.Indirect(rv.Index(i))
+		record := make([]string, len(fields))
+		if item.IsValid() {
+			for j, f := range fields {
+				record[j] = fmt.Sprint(item.Field(f).Interface())
+			}
+		}
+		records = append(records, record)
+	}
+	return records, nil
+}
@@ -0,0 +1,116 @@
+package result
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+)
+
+type user struct {
+	ID   int    `csv:"id" xml:"id"`
+	Name string `csv:"name" xml:"name"`
+}
+
+func TestWriteNegotiation(t *testing.T) {
+	tt := []struct {
+		accept      string
+		contentType string
+		body        string
+	}{
+		{accept: "", contentType: JSONMediaType, body: "[{\"ID\":1,\"Name\":\"Ann\"},{\"ID\":2,\"Name\":\"Bob\"}]\n"},
+		{accept: "text/csv", contentType: CSVMediaType, body: "id,name\n1,Ann\n2,Bob\n"},
+		{accept: "application/xml;q=0.5, text/*;q=0.9", contentType: CSVMediaType, body: "id,name\n1,Ann\n2,Bob\n"},
+		{accept: "application/xml", contentType: XMLMediaType, body: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<items><user><id>1</id><name>Ann</name></user><user><id>2</id><name>Bob</name></user></items>"},
+		{accept: "*/*, application/json;q=0", contentType: XMLMediaType, body: "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<items><user><id>1</id><name>Ann</name></user><user><id>2</id><name>Bob</name></user></items>"},
+		{accept: "image/png", contentType: JSONMediaType, body: "[{\"ID\":1,\"Name\":\"Ann\"},{\"ID\":2,\"Name\":\"Bob\"}]\n"},
+	}
+	for _, tc := range tt {
+		req := httptest.NewRequest("GET", "/users", nil)
+		req.Header.Set("Accept", tc.accept)
+		rw := httptest.NewRecorder()
+		OK([]user{{1, "Ann"}, {2, "Bob"}}).Write(rw, req)
+
+		if ct := rw.Header().Get("Content-Type"); ct != tc.contentType {
+			t.Errorf("accept %q: expected %s but got %s", tc.accept, tc.contentType, ct)
+		}
+		if rw.Body.String() != tc.body {
+			t.Errorf("accept %q: expected %q but got %q", tc.accept, tc.body, rw.Body.String())
+		}
+	}
+}
+
+func TestWriteUnsupportedValue(t *testing.T) {
+	tt := []struct {
+		accept      string
+		value       interface{}
+		status      int
+		contentType string
+		body        string
+	}{
+		{accept: "text/csv", value: user{1, "Ann"}, status: http.StatusOK, contentType: JSONMediaType, body: "{\"ID\":1,\"Name\":\"Ann\"}\n"},
+		{accept: "application/xml", value: map[string]int{"a": 1}, status: http.StatusOK, contentType: JSONMediaType, body: "{\"a\":1}\n"},
+		{accept: "application/xml", value: []map[string]int{{"a": 1}}, status: http.StatusOK, contentType: JSONMediaType, body: "[{\"a\":1}]\n"},
+		{accept: "text/csv, application/json;q=0", value: user{1, "Ann"}, status: http.StatusNotAcceptable},
+	}
+	for _, tc := range tt {
+		req := httptest.NewRequest("GET", "/users", nil)
+		req.Header.Set("Accept", tc.accept)
+		rw := httptest.NewRecorder()
+		if err := OK(tc.value).Write(rw, req); err != nil {
+			t.Errorf("accept %q: unexpected error %v", tc.accept, err)
+		}
+		if rw.Code != tc.status {
+			t.Errorf("accept %q: expected %d but got %d", tc.accept, tc.status, rw.Code)
+		}
+		if tc.contentType != "" && (rw.Header().Get("Content-Type") != tc.contentType || rw.Body.String() != tc.body) {
+			t.Errorf("accept %q: expected %s %q but got %s %q", tc.accept, tc.contentType, tc.body, rw.Header().Get("Content-Type"), rw.Body.String())
+		}
+	}
+}
+
+func TestWriteEncodingError(t *testing.T) {
+	req := httptest.NewRequest("GET", "/users", nil)
+	rw := httptest.NewRecorder()
+	if err := OK(make(chan int)).Write(rw, req); err == nil {
+		t.Error("expected the encoding error")
+	}
+	if rw.Code != http.StatusInternalServerError {
+		t.Errorf("expected 500 but got %d", rw.Code)
+	}
+
+	var reported error
+	Handler(func(*http.Request) *Result { return OK(make(chan int)) }, func(r *http.Request, err error) {
+		if r != req {
+			t.Error("expected the request of the failed write")
+		}
+		reported = err
+	}).ServeHTTP(httptest.NewRecorder(), req)
+	if reported == nil {
+		t.Error("expected the encoding error to be reported")
+	}
+}
+
+func TestWriteStatusAndHeaders(t *testing.T) {
+	req := httptest.NewRequest("POST", "/users", nil)
+
+	rw := httptest.NewRecorder()
+	HandlerFunc(func(*http.Request) *Result {
+		return Created(user{2, "Bob"}, "/users/2").WithETag("v1")
+	}).ServeHTTP(rw, req)
+	if rw.Code != http.StatusCreated || rw.Header().Get("Location") != "/users/2" || rw.Header().Get("ETag") != `"v1"` {
+		t.Errorf("unexpected response %d %v", rw.Code, rw.Header())
+	}
+
+	rw = httptest.NewRecorder()
+	NoContent().Write(rw, req)
+	if rw.Code != http.StatusNoContent || rw.Body.Len() != 0 {
+		t.Errorf("expected empty 204 but got %d %q", rw.Code, rw.Body.String())
+	}
+
+	rw = httptest.NewRecorder()
+	req.Header.Set("Accept", "application/xml")
+	Fail(NotFound, "no user").Write(rw, req)
+	if rw.Code != http.StatusNotFound || rw.Body.String() != "<?xml version=\"1.0\" encoding=\"UTF-8\"?>\n<error><code>NotFound</code><message>no user</message></error>" {
+		t.Errorf("unexpected error response %d %q", rw.Code, rw.Body.String())
+	}
+}
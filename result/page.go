@@ -0,0 +1,259 @@
+package result
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Paging query parameters used in the links of pages
+const (
+	OffsetParam = "offset"
+	LimitParam  = "limit"
+	CursorParam = "cursor"
+)
+
+// Paging response headers
+const (
+	LinkHeader       = "Link"
+	TotalCountHeader = "X-Total-Count"
+)
+
+// ErrInvalidCursor is the cause of the errors returned by CursorCodec.Decode, check it with errors.Is
+var ErrInvalidCursor = errors.New("invalid page cursor")
+
+// newInvalidCursor returns a new InvalidArgument error caused by ErrInvalidCursor, callers may add details to it
+func newInvalidCursor() *Error {
+	return Wrap(ErrInvalidCursor, InvalidArgument, ErrInvalidCursor.Error())
+}
+
+// Link is a RFC 8288 web link, e.g. the next page
+type Link struct {
+	URL string
+	Rel string
+}
+
+// Pager is implemented by the page types, it gives the navigation links of a page
+type Pager interface {
+	Links(base *url.URL) []Link
+}
+
+// PageInfo holds the paging fields of a gRPC list response, following the page_token convention
+type PageInfo struct {
+	NextPageToken string
+	PrevPageToken string
+	// TotalSize is negative when unknown
+	TotalSize int
+}
+
+// Page is an offset/limit page of items
+type Page[T any] struct {
+	Items  []T `json:"items"`
+	Offset int `json:"offset"`
+	Limit  int `json:"limit"`
+	// Total number of items, negative when unknown
+	Total int `json:"total"`
+}
+
+// CursorPage is a page of items navigated with opaque cursors
+type CursorPage[T any] struct {
+	Items      []T    `json:"items"`
+	NextCursor string `json:"nextCursor,omitempty"`
+	PrevCursor string `json:"prevCursor,omitempty"`
+}
+
+func NewPage[T any](items []T, offset, limit, total int) Page[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return Page[T]{Items: items, Offset: offset, Limit: limit, Total: total}
+}
+
+func NewCursorPage[T any](items []T, nextCursor, prevCursor string) CursorPage[T] {
+	if items == nil {
+		items = []T{}
+	}
+	return CursorPage[T]{Items: items, NextCursor: nextCursor, PrevCursor: prevCursor}
+}
+
+func (p Page[T]) HasNext() bool {
+	if p.Total < 0 {
+		return p.Limit > 0 && len(p.Items) >= p.Limit
+	}
+	return p.Offset+len(p.Items) < p.Total
+}
+
+func (p Page[T]) HasPrev() bool {
+	return p.Offset > 0
+}
+
+// Links returns the first, prev, next and last links of the page, built on the query of base.
+// Without a positive limit the pages cannot be computed, prev, next and last are omitted.
+func (p Page[T]) Links(base *url.URL) []Link {
+	var links []Link
+	link := func(offset int, rel string) {
+		links = append(links, Link{URL: withQuery(base, map[string]string{
+			OffsetParam: strconv.Itoa(offset),
+			LimitParam:  strconv.Itoa(p.Limit),
+		}), Rel: rel})
+	}
+	link(0, "first")
+	if p.Limit <= 0 {
+		return links
+	}
+	if p.HasPrev() {
+		prev := p.Offset - p.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		link(prev, "prev")
+	}
+	if p.HasNext() {
+		link(p.Offset+len(p.Items), "next")
+	}
+	if p.Total >= 0 {
+		last := 0
+		if p.Total > 0 {
+			last = (p.Total - 1) / p.Limit * p.Limit
+		}
+		link(last, "last")
+	}
+	return links
+}
+
+// TotalCount returns the total number of items, false when unknown
+func (p Page[T]) TotalCount() (int, bool) {
+	return p.Total, p.Total >= 0
+}
+
+// PageInfo returns the gRPC paging fields, tokens are the offsets of the next and previous pages
+func (p Page[T]) PageInfo() PageInfo {
+	info := PageInfo{TotalSize: p.Total}
+	if p.HasNext() {
+		info.NextPageToken = strconv.Itoa(p.Offset + len(p.Items))
+	}
+	if p.HasPrev() {
+		prev := p.Offset - p.Limit
+		if prev < 0 {
+			prev = 0
+		}
+		info.PrevPageToken = strconv.Itoa(prev)
+	}
+	return info
+}
+
+// Links returns the prev and next links of the page, built on the query of base
+func (p CursorPage[T]) Links(base *url.URL) []Link {
+	var links []Link
+	if p.PrevCursor != "" {
+		links = append(links, Link{URL: withQuery(base, map[string]string{CursorParam: p.PrevCursor}), Rel: "prev"})
+	}
+	if p.NextCursor != "" {
+		links = append(links, Link{URL: withQuery(base, map[string]string{CursorParam: p.NextCursor}), Rel: "next"})
+	}
+	return links
+}
+
+// PageInfo returns the gRPC paging fields, tokens are the cursors and the total size is unknown
+func (p CursorPage[T]) PageInfo() PageInfo {
+	return PageInfo{
+		NextPageToken: p.NextCursor,
+		PrevPageToken: p.PrevCursor,
+		TotalSize:     -1,
+	}
+}
+
+// FormatLinks formats links as a RFC 8288 Link header value
+func FormatLinks(links []Link) string {
+	values := make([]string, 0, len(links))
+	for _, l := range links {
+		values = append(values, fmt.Sprintf("<%s>; rel=\"%s\"", l.URL, l.Rel))
+	}
+	return strings.Join(values, ", ")
+}
+
+// OKPage returns a success result of the page with the Link and X-Total-Count headers,
+// links are relative to requestURL, usually the URL of the request being served
+func OKPage(page Pager, requestURL *url.URL) *Result {
+	r := OK(page)
+	if links := page.Links(requestURL); len(links) > 0 {
+		r.WithHeader(LinkHeader, FormatLinks(links))
+	}
+	if counter, ok := page.(interface{ TotalCount() (int, bool) }); ok {
+		if total, known := counter.TotalCount(); known {
+			r.WithHeader(TotalCountHeader, strconv.Itoa(total))
+		}
+	}
+	return r
+}
+
+func withQuery(base *url.URL, params map[string]string) string {
+	u := url.URL{}
+	if base != nil {
+		u = *base
+	}
+	q := u.Query()
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	u.RawQuery = q.Encode()
+	return u.String()
+}
+
+// CursorCodec encodes page positions into opaque cursors, signed with HMAC-SHA256 when a secret is set
+// so clients cannot forge them
+type CursorCodec struct {
+	secret []byte
+}
+
+func NewCursorCodec(secret []byte) *CursorCodec {
+	return &CursorCodec{secret: secret}
+}
+
+// Encode returns the cursor of v, v is serialized as JSON
+func (c *CursorCodec) Encode(v interface{}) (string, error) {
+	payload, err := json.Marshal(v)
+	if err != nil {
+		return "", err
+	}
+	cursor := base64.RawURLEncoding.EncodeToString(payload)
+	if len(c.secret) > 0 {
+		cursor += "." + base64.RawURLEncoding.EncodeToString(c.sign(payload))
+	}
+	return cursor, nil
+}
+
+// Decode reads cursor into v, it returns an InvalidArgument *Error caused by ErrInvalidCursor
+// when the cursor is malformed or its signature does not match
+func (c *CursorCodec) Decode(cursor string, v interface{}) error {
+	encoded, signature, signed := strings.Cut(cursor, ".")
+	if signed != (len(c.secret) > 0) {
+		return newInvalidCursor()
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return newInvalidCursor()
+	}
+	if signed {
+		mac, err := base64.RawURLEncoding.DecodeString(signature)
+		if err != nil || !hmac.Equal(mac, c.sign(payload)) {
+			return newInvalidCursor()
+		}
+	}
+	if err := json.Unmarshal(payload, v); err != nil {
+		return newInvalidCursor()
+	}
+	return nil
+}
+
+func (c *CursorCodec) sign(payload []byte) []byte {
+	mac := hmac.New(sha256.New, c.secret)
+	mac.Write(payload)
+	return mac.Sum(nil)
+}
@@ -0,0 +1,89 @@
+package result
+
+import (
+	"errors"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+)
+
+func TestOKPageHeaders(t *testing.T) {
+	requestURL, _ := url.Parse("/users?sort=name&offset=10&limit=10")
+	page := NewPage([]string{"a", "b"}, 10, 10, 25)
+
+	rw := httptest.NewRecorder()
+	OKPage(page, requestURL).WriteResponse(rw, runtime.JSONProducer())
+
+	expectedLink := `</users?limit=10&offset=0&sort=name>; rel="first", ` +
+		`</users?limit=10&offset=0&sort=name>; rel="prev", ` +
+		`</users?limit=10&offset=12&sort=name>; rel="next", ` +
+		`</users?limit=10&offset=20&sort=name>; rel="last"`
+	if link := rw.Header().Get(LinkHeader); link != expectedLink {
+		t.Errorf("expected %s but got %s", expectedLink, link)
+	}
+	if total := rw.Header().Get(TotalCountHeader); total != "25" {
+		t.Errorf("expected total 25 but got %s", total)
+	}
+	if info := page.PageInfo(); info.NextPageToken != "12" || info.PrevPageToken != "0" {
+		t.Errorf("unexpected page info %+v", info)
+	}
+}
+
+func TestPageLinks(t *testing.T) {
+	tt := []struct {
+		page     Page[string]
+		expected []string
+	}{
+		{page: NewPage([]string{"a"}, 0, 1, 3), expected: []string{"first", "next", "last"}},
+		{page: NewPage([]string{"a"}, 2, 1, 3), expected: []string{"first", "prev", "last"}},
+		{page: NewPage([]string{"a"}, 1, 1, -1), expected: []string{"first", "prev", "next"}},
+		{page: NewPage([]string{"a", "b"}, 10, 0, 25), expected: []string{"first"}},
+		{page: NewPage([]string{"a", "b"}, 10, -1, -1), expected: []string{"first"}},
+	}
+	for i, tc := range tt {
+		var rels []string
+		for _, l := range tc.page.Links(&url.URL{Path: "/items"}) {
+			rels = append(rels, l.Rel)
+		}
+		if strings.Join(rels, ",") != strings.Join(tc.expected, ",") {
+			t.Errorf("#%d: expected %v but got %v", i, tc.expected, rels)
+		}
+	}
+}
+
+func TestCursorCodec(t *testing.T) {
+	type position struct {
+		LastID int `json:"lastId"`
+	}
+	codec := NewCursorCodec([]byte("secret"))
+	cursor, err := codec.Encode(position{LastID: 42})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var decoded position
+	if err := codec.Decode(cursor, &decoded); err != nil || decoded.LastID != 42 {
+		t.Errorf("expected 42 but got %v, %v", decoded.LastID, err)
+	}
+
+	tampered, _ := NewCursorCodec(nil).Encode(position{LastID: 43})
+	for _, c := range []string{tampered, tampered + "." + cursor[len(cursor)-10:], "not a cursor"} {
+		err := codec.Decode(c, &decoded)
+		if !errors.Is(err, ErrInvalidCursor) || CodeOf(err) != InvalidArgument {
+			t.Errorf("expected invalid cursor for %q but got %v", c, err)
+		}
+	}
+	first, second := codec.Decode("not a cursor", &decoded), codec.Decode("not a cursor", &decoded)
+	if first == second {
+		t.Error("expected a new error on each call so callers can change it")
+	}
+
+	page := NewCursorPage([]int{1}, cursor, "")
+	links := page.Links(&url.URL{Path: "/items"})
+	if len(links) != 1 || links[0].Rel != "next" || links[0].URL != "/items?cursor="+cursor {
+		t.Errorf("unexpected links %v", links)
+	}
+}
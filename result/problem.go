@@ -0,0 +1,90 @@
+package result
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"unicode"
+
+	"github.com/jedrp/go-core/log/logctx"
+)
+
+// ProblemMediaType is the media type of RFC 7807/9457 problem details
+const ProblemMediaType = "application/problem+json"
+
+// Problem is an RFC 7807/9457 problem details object,
+// Code and Details are extension members carrying the original Error
+type Problem struct {
+	Type     string    `json:"type"`
+	Title    string    `json:"title,omitempty"`
+	Status   int       `json:"status,omitempty"`
+	Detail   string    `json:"detail,omitempty"`
+	Instance string    `json:"instance,omitempty"`
+	Code     ErrorCode `json:"code,omitempty"`
+	Details  Details   `json:"details,omitempty"`
+}
+
+var problemSetting = struct {
+	enabled     bool
+	typeBaseURI string
+}{}
+
+// ConfigureProblemDetails makes WriteResponse render errors as application/problem+json.
+// typeBaseURI is the prefix of the problem type, e.g. "https://errors.example.com" gives
+// "https://errors.example.com/not-found"; when empty the type is "about:blank".
+func ConfigureProblemDetails(enabled bool, typeBaseURI string) {
+	problemSetting.enabled = enabled
+	problemSetting.typeBaseURI = strings.TrimSuffix(typeBaseURI, "/")
+}
+
+// NewProblem builds the problem details of err, the instance is the request ID found in ctx
+func NewProblem(ctx context.Context, err *Error) *Problem {
+	if err == nil {
+		err = &Error{Code: Unknown}
+	}
+	status := HTTPStatus(err.Code)
+	p := &Problem{
+		Type:    "about:blank",
+		Title:   http.StatusText(status),
+		Status:  status,
+		Detail:  err.Message,
+		Code:    err.Code,
+		Details: err.Details,
+	}
+	if problemSetting.typeBaseURI != "" {
+		p.Type = problemSetting.typeBaseURI + "/" + strings.ToLower(strings.Join(splitWords(string(err.Code)), "-"))
+		p.Title = strings.Join(splitWords(string(err.Code)), " ")
+	}
+	if ctx != nil {
+		if requestID := logctx.StringField(ctx, logctx.RequestID); requestID != "" {
+			p.Instance = requestID
+		}
+	}
+	return p
+}
+
+// Write writes the problem with its status and the problem+json content type
+func (p *Problem) Write(rw http.ResponseWriter) {
+	rw.Header().Set("Content-Type", ProblemMediaType)
+	rw.WriteHeader(p.Status)
+	if err := json.NewEncoder(rw).Encode(p); err != nil {
+		panic(err) // let the recovery middleware deal with this
+	}
+}
+
+// splitWords splits a CamelCase code, e.g. "InvalidArgument" gives ["Invalid", "Argument"]
+func splitWords(s string) []string {
+	var words []string
+	start := 0
+	for i, r := range s {
+		if i > start && unicode.IsUpper(r) {
+			words = append(words, s[start:i])
+			start = i
+		}
+	}
+	if start < len(s) {
+		words = append(words, s[start:])
+	}
+	return words
+}
@@ -0,0 +1,47 @@
+package result
+
+import (
+	"context"
+	"encoding/json"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/go-openapi/runtime"
+	"github.com/jedrp/go-core/log"
+)
+
+func TestWriteResponseProblem(t *testing.T) {
+	ConfigureProblemDetails(true, "https://errors.example.com/")
+	defer ConfigureProblemDetails(false, "")
+
+	ctx := context.WithValue(context.Background(), log.RequestID, "req-1")
+	err := NewNotFound("user 42 not found").WithDetails(&ResourceInfo{ResourceType: "user", ResourceName: "42"})
+	rw := httptest.NewRecorder()
+	FailWith(err).WithContext(ctx).WriteResponse(rw, runtime.JSONProducer())
+
+	if rw.Code != 404 {
+		t.Errorf("expected 404 but got %d", rw.Code)
+	}
+	if ct := rw.Header().Get("Content-Type"); ct != ProblemMediaType {
+		t.Errorf("expected %s but got %s", ProblemMediaType, ct)
+	}
+	var p Problem
+	if e := json.Unmarshal(rw.Body.Bytes(), &p); e != nil {
+		t.Fatal(e)
+	}
+	expected := Problem{
+		Type:     "https://errors.example.com/not-found",
+		Title:    "Not Found",
+		Status:   404,
+		Detail:   "user 42 not found",
+		Instance: "req-1",
+		Code:     NotFound,
+	}
+	if p.Type != expected.Type || p.Title != expected.Title || p.Status != expected.Status ||
+		p.Detail != expected.Detail || p.Instance != expected.Instance || p.Code != expected.Code {
+		t.Errorf("expected %+v but got %+v", expected, p)
+	}
+	if len(p.Details) != 1 {
+		t.Errorf("expected details extension member but got %v", p.Details)
+	}
+}
@@ -1,26 +1,46 @@
 package result
 
 import (
+	"context"
 	"fmt"
 	"net/http"
 
 	"github.com/go-openapi/runtime"
 )
 
-type Error struct {
-	Code    ErrorCode `json:"code,omitempty"`
-	Message string    `json:"message,omitempty"`
-}
-
 type Result struct {
-	Value interface{}
-	Error *Error
+	Value   interface{}
+	Error   *Error
+	ctx     context.Context
+	headers http.Header
+	status  int
 }
 
 func OK(v interface{}) *Result {
 	return &Result{
-		v,
-		nil,
+		Value: v,
+	}
+}
+
+// WithHeader adds a header written with the response
+func (r *Result) WithHeader(key, value string) *Result {
+	if r.headers == nil {
+		r.headers = make(http.Header)
+	}
+	r.headers.Add(key, value)
+	return r
+}
+
+// WithContext sets the request context used when writing the response, e.g. for the problem instance
+func (r *Result) WithContext(ctx context.Context) *Result {
+	r.ctx = ctx
+	return r
+}
+
+// FailWith returns a failed result from err, keeping the code of a wrapped *Error
+func FailWith(err error) *Result {
+	return &Result{
+		Error: ErrorFrom(err),
 	}
 }
 
@@ -34,8 +54,7 @@ func (r *Result) IsFailure() bool {
 
 func Failf(code ErrorCode, f string, o ...interface{}) *Result {
 	return &Result{
-		nil,
-		&Error{
+		Error: &Error{
 			Code:    code,
 			Message: fmt.Sprintf(f, o...),
 		},
@@ -43,8 +62,7 @@ func Failf(code ErrorCode, f string, o ...interface{}) *Result {
 }
 func Fail(code ErrorCode, m string) *Result {
 	return &Result{
-		nil,
-		&Error{
+		Error: &Error{
 			Code:    code,
 			Message: m,
 		},
@@ -53,27 +71,20 @@ func Fail(code ErrorCode, m string) *Result {
 
 // Implement Responder interface (Responder is an interface for types to implement, when they want to be considered for writing HTTP responses)
 func (r *Result) WriteResponse(rw http.ResponseWriter, producer runtime.Producer) {
+	r.writeHeaders(rw)
 	if r.Error == nil {
-		rw.WriteHeader(200)
+		status := r.statusCode()
+		rw.WriteHeader(status)
+		if status == http.StatusNoContent || status == http.StatusNotModified {
+			return
+		}
 		if err := producer.Produce(rw, r.Value); err != nil {
 			panic(err) // let the recovery middleware deal with this
 		}
+	} else if problemSetting.enabled {
+		NewProblem(r.ctx, r.Error).Write(rw)
 	} else {
-		code := r.Error.Code
-		switch code {
-		case InvalidArgument:
-			rw.WriteHeader(400)
-		case NotFound:
-			rw.WriteHeader(404)
-		case Aborted:
-			rw.WriteHeader(412)
-		case Unauthenticated:
-			rw.WriteHeader(401)
-		case PermissionDenied:
-			rw.WriteHeader(403)
-		default:
-			rw.WriteHeader(500)
-		}
+		rw.WriteHeader(HTTPStatus(r.Error.Code))
 		if err := producer.Produce(rw, r.Error); err != nil {
 			panic(err) // let the recovery middleware deal with this
 		}
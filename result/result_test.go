@@ -1,6 +1,9 @@
 package result
 
-import "testing"
+import (
+	"strings"
+	"testing"
+)
 
 func TestResult(t *testing.T) {
 	tt := []struct {
@@ -25,3 +28,32 @@ func TestResult(t *testing.T) {
 		}
 	}
 }
+
+func TestTypedResult(t *testing.T) {
+	r := Map(Ok(2), func(v int) string { return strings.Repeat("a", v) })
+	if !r.IsSuccess() || r.Value() != "aa" {
+		t.Errorf("expected aa but got %v", r.Value())
+	}
+
+	failed := Bind(Ok(1), func(v int) Of[int] { return Failure[int](NotFound, "missing") })
+	if !failed.IsFailure() || failed.Err().Code != NotFound {
+		t.Errorf("expected NotFound failure but got %v", failed.Err())
+	}
+	if Map(failed, func(v int) int { return v + 1 }).Err() != failed.Err() {
+		t.Error("expected failure to be passed through Map")
+	}
+	if v, err := failed.Get(); err == nil || v != 0 {
+		t.Errorf("expected error but got %v, %v", v, err)
+	}
+	if v := failed.Recover(func(e *Error) int { return -1 }).Value(); v != -1 {
+		t.Errorf("expected recovered value -1 but got %v", v)
+	}
+
+	untyped := Ok(5).ToResult()
+	if Typed[int](untyped).Value() != 5 {
+		t.Error("expected round trip through Result")
+	}
+	if Typed[string](untyped).Err().Code != Internal {
+		t.Error("expected Internal for mismatched value type")
+	}
+}
@@ -0,0 +1,127 @@
+package result
+
+import (
+	"fmt"
+)
+
+// Of is the typed counterpart of Result, it holds either a value of type T or an error.
+// The zero value is a success holding the zero value of T.
+type Of[T any] struct {
+	value T
+	err   *Error
+}
+
+// Ok returns a success result holding v
+func Ok[T any](v T) Of[T] {
+	return Of[T]{value: v}
+}
+
+// Failure returns a failed result of type T
+func Failure[T any](code ErrorCode, m string) Of[T] {
+	return Of[T]{err: &Error{Code: code, Message: m}}
+}
+
+// Failuref returns a failed result of type T with a formatted message
+func Failuref[T any](code ErrorCode, f string, o ...interface{}) Of[T] {
+	return Failure[T](code, fmt.Sprintf(f, o...))
+}
+
+// FailureFrom returns a failed result of type T holding err, a nil err is reported as Unknown
+func FailureFrom[T any](err *Error) Of[T] {
+	if err == nil {
+		err = &Error{Code: Unknown}
+	}
+	return Of[T]{err: err}
+}
+
+// FromValue converts the usual (value, error) pair to a typed result, keeping the code of a wrapped *Error
+func FromValue[T any](v T, err error) Of[T] {
+	if err != nil {
+		return Of[T]{err: ErrorFrom(err)}
+	}
+	return Ok(v)
+}
+
+func (r Of[T]) IsSuccess() bool {
+	return r.err == nil
+}
+
+func (r Of[T]) IsFailure() bool {
+	return r.err != nil
+}
+
+// Value returns the value, the zero value of T when the result is a failure
+func (r Of[T]) Value() T {
+	return r.value
+}
+
+// ValueOr returns the value or def when the result is a failure
+func (r Of[T]) ValueOr(def T) T {
+	if r.err != nil {
+		return def
+	}
+	return r.value
+}
+
+// Err returns the error, nil when the result is a success
+func (r Of[T]) Err() *Error {
+	return r.err
+}
+
+// Get converts the result back to the usual (value, error) pair
+func (r Of[T]) Get() (T, error) {
+	if r.err != nil {
+		return r.value, r.err
+	}
+	return r.value, nil
+}
+
+// Recover turns a failure into a success using the value returned by f
+func (r Of[T]) Recover(f func(*Error) T) Of[T] {
+	if r.err == nil {
+		return r
+	}
+	return Ok(f(r.err))
+}
+
+// ToResult converts to the untyped Result, e.g. to use WriteResponse
+func (r Of[T]) ToResult() *Result {
+	if r.err != nil {
+		return &Result{Error: r.err}
+	}
+	return &Result{Value: r.value}
+}
+
+// Map applies f to the value of a success result, failures are passed through
+func Map[T, U any](r Of[T], f func(T) U) Of[U] {
+	if r.err != nil {
+		return Of[U]{err: r.err}
+	}
+	return Ok(f(r.value))
+}
+
+// Bind chains a result returning function on the value of a success result, failures are passed through
+func Bind[T, U any](r Of[T], f func(T) Of[U]) Of[U] {
+	if r.err != nil {
+		return Of[U]{err: r.err}
+	}
+	return f(r.value)
+}
+
+// Typed converts an untyped Result, failing with Internal when its value is not a T as the mismatch is a programming error
+func Typed[T any](r *Result) Of[T] {
+	if r == nil {
+		return FailureFrom[T](nil)
+	}
+	if r.Error != nil {
+		return Of[T]{err: r.Error}
+	}
+	if r.Value == nil {
+		return Of[T]{}
+	}
+	v, ok := r.Value.(T)
+	if !ok {
+		return Failuref[T](Internal, "result value of type %T is not a %T", r.Value, *new(T))
+	}
+	return Ok(v)
+}
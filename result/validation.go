@@ -0,0 +1,177 @@
+package result
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	openapierrors "github.com/go-openapi/errors"
+)
+
+// Validation accumulates the field violations of a request and reports them as one InvalidArgument error.
+// Nested and indexed scopes share the violations of their parent:
+//
+//	v := result.NewValidation()
+//	v.Check(req.Name != "", "name", "required", "name is required", nil)
+//	for i, item := range req.Items {
+//		v.Field("items").Index(i).Check(item.Quantity > 0, "quantity", "min", "quantity must be positive", map[string]interface{}{"min": 1})
+//	}
+//	if err := v.Err(); err != nil {
+//		return result.FailWith(err)
+//	}
+type Validation struct {
+	path       string
+	violations *[]FieldViolation
+}
+
+var openAPIRules = map[int32]string{
+	openapierrors.InvalidTypeCode:           "type",
+	openapierrors.RequiredFailCode:          "required",
+	openapierrors.TooLongFailCode:           "maxLength",
+	openapierrors.TooShortFailCode:          "minLength",
+	openapierrors.PatternFailCode:           "pattern",
+	openapierrors.EnumFailCode:              "enum",
+	openapierrors.MultipleOfFailCode:        "multipleOf",
+	openapierrors.MaxFailCode:               "maximum",
+	openapierrors.MinFailCode:               "minimum",
+	openapierrors.UniqueFailCode:            "uniqueItems",
+	openapierrors.MaxItemsFailCode:          "maxItems",
+	openapierrors.MinItemsFailCode:          "minItems",
+	openapierrors.NoAdditionalItemsCode:     "additionalItems",
+	openapierrors.TooFewPropertiesCode:      "minProperties",
+	openapierrors.TooManyPropertiesCode:     "maxProperties",
+	openapierrors.UnallowedPropertyCode:     "additionalProperties",
+	openapierrors.FailedAllPatternPropsCode: "patternProperties",
+}
+
+func NewValidation() *Validation {
+	return &Validation{violations: &[]FieldViolation{}}
+}
+
+// Field returns a scope whose violations are reported under the nested field name
+func (v *Validation) Field(name string) *Validation {
+	return &Validation{path: joinPath(v.path, name), violations: v.violations}
+}
+
+// Index returns a scope whose violations are reported under the array index i
+func (v *Validation) Index(i int) *Validation {
+	return &Validation{path: v.path + "[" + strconv.Itoa(i) + "]", violations: v.violations}
+}
+
+// Add records a violation of rule on field, relative to the scope. An empty field reports the scope itself.
+func (v *Validation) Add(field, rule, description string, params map[string]interface{}) *Validation {
+	*v.violations = append(*v.violations, FieldViolation{
+		Field:       joinPath(v.path, field),
+		Description: description,
+		Rule:        rule,
+		Params:      params,
+	})
+	return v
+}
+
+// Check records the violation when ok is false and returns ok
+func (v *Validation) Check(ok bool, field, rule, description string, params map[string]interface{}) bool {
+	if !ok {
+		v.Add(field, rule, description, params)
+	}
+	return ok
+}
+
+// AddError records the violations of a go-openapi validation error, e.g. returned by Validate(strfmt.Registry),
+// relative to the scope
+func (v *Validation) AddError(err error) *Validation {
+	for _, fv := range openAPIViolations(err) {
+		v.Add(fv.Field, fv.Rule, fv.Description, fv.Params)
+	}
+	return v
+}
+
+func (v *Validation) Valid() bool {
+	return len(*v.violations) == 0
+}
+
+func (v *Validation) Violations() []FieldViolation {
+	return *v.violations
+}
+
+// Err returns an InvalidArgument error holding all the violations, nil when there is none
+func (v *Validation) Err() *Error {
+	if v.Valid() {
+		return nil
+	}
+	violations := make([]FieldViolation, len(*v.violations))
+	copy(violations, *v.violations)
+	return NewErrorf(InvalidArgument, "request has %d invalid field(s)", len(violations)).
+		WithDetails(&BadRequest{FieldViolations: violations})
+}
+
+// FromValidationError converts a go-openapi validation error, usually a CompositeError returned by
+// Validate(strfmt.Registry), to an InvalidArgument error with one field violation per validation
+func FromValidationError(err error) *Error {
+	if err == nil {
+		return nil
+	}
+	if e := NewValidation().AddError(err).Err(); e != nil {
+		return e
+	}
+	return NewError(InvalidArgument, err.Error())
+}
+
+func openAPIViolations(err error) []FieldViolation {
+	var (
+		composite  *openapierrors.CompositeError
+		validation *openapierrors.Validation
+	)
+	switch {
+	case errors.As(err, &composite):
+		var violations []FieldViolation
+		for _, e := range composite.Errors {
+			violations = append(violations, openAPIViolations(e)...)
+		}
+		return violations
+	case errors.As(err, &validation):
+		fv := FieldViolation{
+			Field:       openAPIPath(validation.Name),
+			Description: validation.Error(),
+			Rule:        openAPIRules[validation.Code()],
+		}
+		if len(validation.Values) > 0 {
+			fv.Params = map[string]interface{}{"values": validation.Values}
+		} else if validation.Value != nil {
+			fv.Params = map[string]interface{}{"value": validation.Value}
+		}
+		return []FieldViolation{fv}
+	default:
+		return []FieldViolation{{Description: err.Error()}}
+	}
+}
+
+// openAPIPath converts the go-openapi "items.0.name" notation to "items[0].name"
+func openAPIPath(name string) string {
+	var b strings.Builder
+	for i, segment := range strings.Split(name, ".") {
+		if _, err := strconv.Atoi(segment); err == nil && i > 0 {
+			fmt.Fprintf(&b, "[%s]", segment)
+			continue
+		}
+		if i > 0 {
+			b.WriteByte('.')
+		}
+		b.WriteString(segment)
+	}
+	return b.String()
+}
+
+func joinPath(parent, field string) string {
+	switch {
+	case parent == "":
+		return field
+	case field == "":
+		return parent
+	case strings.HasPrefix(field, "["):
+		return parent + field
+	default:
+		return parent + "." + field
+	}
+}
@@ -0,0 +1,57 @@
+package result
+
+import (
+	"testing"
+
+	openapierrors "github.com/go-openapi/errors"
+)
+
+func TestValidationBuilder(t *testing.T) {
+	v := NewValidation()
+	if v.Err() != nil {
+		t.Error("expected no error without violations")
+	}
+	v.Check(false, "name", "required", "name is required", nil)
+	v.Check(true, "email", "format", "email is invalid", nil)
+	items := v.Field("order").Field("items")
+	items.Index(0).Add("quantity", "min", "quantity must be positive", map[string]interface{}{"min": 1})
+	items.Index(2).Add("", "unique", "duplicated item", nil)
+
+	err := v.Err()
+	if err == nil || err.Code != InvalidArgument {
+		t.Fatalf("expected InvalidArgument but got %v", err)
+	}
+	violations := err.Details[0].(*BadRequest).FieldViolations
+	expected := []string{"name", "order.items[0].quantity", "order.items[2]"}
+	if len(violations) != len(expected) {
+		t.Fatalf("expected %d violations but got %v", len(expected), violations)
+	}
+	for i, field := range expected {
+		if violations[i].Field != field {
+			t.Errorf("expected field %s but got %s", field, violations[i].Field)
+		}
+	}
+	if violations[1].Rule != "min" || violations[1].Params["min"] != 1 {
+		t.Errorf("unexpected rule %s %v", violations[1].Rule, violations[1].Params)
+	}
+}
+
+func TestFromValidationError(t *testing.T) {
+	err := openapierrors.CompositeValidationError(
+		openapierrors.Required("name", "body"),
+		openapierrors.CompositeValidationError(
+			openapierrors.EnumFail("items.1.kind", "body", "x", []interface{}{"a", "b"}),
+		),
+	)
+	e := FromValidationError(err)
+	violations := e.Details[0].(*BadRequest).FieldViolations
+	if len(violations) != 2 {
+		t.Fatalf("expected 2 violations but got %v", violations)
+	}
+	if violations[0].Field != "name" || violations[0].Rule != "required" {
+		t.Errorf("unexpected violation %+v", violations[0])
+	}
+	if violations[1].Field != "items[1].kind" || violations[1].Rule != "enum" {
+		t.Errorf("unexpected violation %+v", violations[1])
+	}
+}
@@ -0,0 +1,215 @@
+package util
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Tags read by Bind on the fields of the target struct
+const (
+	// SettingTag key of the field in the setting string, fields without it are ignored
+	SettingTag = "setting"
+	// DefaultTag value used when the key is missing
+	DefaultTag = "default"
+	// RequiredTag "true" when the key must be present with a non empty value
+	RequiredTag = "required"
+	// EnumTag comma separated list of the allowed values, matched case-insensitively and bound as listed
+	EnumTag = "enum"
+	// UnitTag unit of a duration given as a bare number, e.g. "s" to keep "readTimeout=30" meaning 30 seconds
+	UnitTag = "unit"
+	// DescriptionTag human readable description of the setting
+	DescriptionTag = "description"
+)
+
+var durationType = reflect.TypeOf(time.Duration(0))
+
+// FieldError is the failure of binding one setting key
+type FieldError struct {
+	Key    string
+	Value  string
+	Reason string
+}
+
+func (e *FieldError) Error() string {
+	if e.Value == "" {
+		return fmt.Sprintf("%s: %s", e.Key, e.Reason)
+	}
+	return fmt.Sprintf("%s: invalid value %q: %s", e.Key, e.Value, e.Reason)
+}
+
+// BindError aggregates the failures of Bind
+type BindError struct {
+	Errors []*FieldError
+}
+
+func (e *BindError) Error() string {
+	lines := make([]string, 0, len(e.Errors)+1)
+	lines = append(lines, "invalid settings:")
+	for _, fe := range e.Errors {
+		lines = append(lines, "  - "+fe.Error())
+	}
+	return strings.Join(lines, "\n")
+}
+
+// BindConfig parses the setting string with GetConfig and binds it into target, see Bind
+func BindConfig(str string, target interface{}) error {
+	config, err := GetConfig(str)
+	if err != nil {
+		return err
+	}
+	return Bind(config, target)
+}
+
+// Bind sets the fields of the struct pointed by target from config using the field tags:
+//
+//	type settings struct {
+//		Host    string        `setting:"host" required:"true"`
+//		Port    int           `setting:"port" default:"80"`
+//		Timeout time.Duration `setting:"timeout" default:"30s" unit:"s"`
+//		Mode    string        `setting:"mode" default:"sync" enum:"sync,async"`
+//		Hosts   []string      `setting:"hosts"`
+//	}
+//
+// Supported types are strings, bools, integers, floats, time.Duration and slices of them written comma separated.
+// All the failures are returned together as a *BindError.
+func Bind(config map[string]string, target interface{}) error {
+	v := reflect.ValueOf(target)
+	if v.Kind() != reflect.Ptr || v.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("bind target must be a pointer to struct, got %T", target)
+	}
+	bindErr := &BindError{}
+	bindStruct(config, v.Elem(), bindErr)
+	if len(bindErr.Errors) > 0 {
+		return bindErr
+	}
+	return nil
+}
+
+func bindStruct(config map[string]string, v reflect.Value, bindErr *BindError) {
+	t := v.Type()
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			bindStruct(config, v.Field(i), bindErr)
+			continue
+		}
+		key := field.Tag.Get(SettingTag)
+		if key == "" || key == "-" || !field.IsExported() {
+			continue
+		}
+		// an empty value is handled as a missing key
+		value := config[key]
+		if value == "" {
+			if field.Tag.Get(RequiredTag) == "true" {
+				bindErr.Errors = append(bindErr.Errors, &FieldError{Key: key, Reason: "required key is missing"})
+				continue
+			}
+			if value = field.Tag.Get(DefaultTag); value == "" {
+				continue
+			}
+		}
+		if enum := field.Tag.Get(EnumTag); enum != "" {
+			allowed, found := enumValue(value, enum)
+			if !found {
+				bindErr.Errors = append(bindErr.Errors, &FieldError{Key: key, Value: value, Reason: "must be one of " + enum})
+				continue
+			}
+			value = allowed
+		}
+		if err := setValue(v.Field(i), value, field.Tag.Get(UnitTag)); err != nil {
+			bindErr.Errors = append(bindErr.Errors, &FieldError{Key: key, Value: redactValue(key, value), Reason: err.Error()})
+		}
+	}
+}
+
+// enumValue returns the allowed value matching value regardless of case, e.g. "info" for "INFO"
+func enumValue(value, enum string) (string, bool) {
+	for _, allowed := range strings.Split(enum, ",") {
+		if allowed = strings.TrimSpace(allowed); strings.EqualFold(allowed, value) {
+			return allowed, true
+		}
+	}
+	return "", false
+}
+
+func setValue(f reflect.Value, value string, unit string) error {
+	if f.Type() == durationType {
+		d, err := parseDuration(value, unit)
+		if err != nil {
+			return err
+		}
+		f.SetInt(int64(d))
+		return nil
+	}
+	switch f.Kind() {
+	case reflect.String:
+		f.SetString(value)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(value)
+		if err != nil {
+			return fmt.Errorf("expected a bool")
+		}
+		f.SetBool(b)
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		n, err := strconv.ParseInt(value, 10, f.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected an integer")
+		}
+		f.SetInt(n)
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		n, err := strconv.ParseUint(value, 10, f.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected a positive integer")
+		}
+		f.SetUint(n)
+	case reflect.Float32, reflect.Float64:
+		n, err := strconv.ParseFloat(value, f.Type().Bits())
+		if err != nil {
+			return fmt.Errorf("expected a number")
+		}
+		f.SetFloat(n)
+	case reflect.Slice:
+		items := splitList(value)
+		slice := reflect.MakeSlice(f.Type(), len(items), len(items))
+		for i, item := range items {
+			if err := setValue(slice.Index(i), item, unit); err != nil {
+				return fmt.Errorf("item %d: %w", i, err)
+			}
+		}
+		f.Set(slice)
+	default:
+		return fmt.Errorf("unsupported field type %s", f.Type())
+	}
+	return nil
+}
+
+func parseDuration(value string, unit string) (time.Duration, error) {
+	if unit != "" {
+		if n, err := strconv.ParseInt(value, 10, 64); err == nil {
+			d, err := time.ParseDuration("1" + unit)
+			if err != nil {
+				return 0, fmt.Errorf("invalid unit %s", unit)
+			}
+			return time.Duration(n) * d, nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil {
+		return 0, fmt.Errorf("expected a duration, e.g. 30s")
+	}
+	return d, nil
+}
+
+func splitList(value string) []string {
+	if strings.TrimSpace(value) == "" {
+		return nil
+	}
+	items := strings.Split(value, ",")
+	for i := range items {
+		items[i] = strings.TrimSpace(items[i])
+	}
+	return items
+}
@@ -0,0 +1,74 @@
+package util
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+	"time"
+)
+
+type testSettings struct {
+	Host     string        `setting:"host" required:"true"`
+	Port     int           `setting:"port" default:"80"`
+	Sniff    bool          `setting:"sniff"`
+	Timeout  time.Duration `setting:"timeout" default:"30" unit:"s"`
+	Mode     string        `setting:"mode" default:"sync" enum:"sync,async"`
+	Hosts    []string      `setting:"hosts"`
+	Ignored  string
+	internal string
+}
+
+func TestBind(t *testing.T) {
+	var s testSettings
+	err := BindConfig("host=localhost;timeout=1m;hosts=a, b;mode=async;sniff=true", &s)
+	if err != nil {
+		t.Fatal(err)
+	}
+	expected := testSettings{
+		Host:    "localhost",
+		Port:    80,
+		Sniff:   true,
+		Timeout: time.Minute,
+		Mode:    "async",
+		Hosts:   []string{"a", "b"},
+	}
+	if !reflect.DeepEqual(s, expected) {
+		t.Errorf("expected %+v but got %+v", expected, s)
+	}
+
+	s = testSettings{}
+	if err := BindConfig("host=h;timeout=45", &s); err != nil || s.Timeout != 45*time.Second {
+		t.Errorf("expected bare timeout in seconds but got %v, %v", s.Timeout, err)
+	}
+
+	s = testSettings{}
+	if err := BindConfig("host=h;mode=ASync", &s); err != nil || s.Mode != "async" {
+		t.Errorf("expected enum matched regardless of case but got %q, %v", s.Mode, err)
+	}
+}
+
+func TestBindAggregatesErrors(t *testing.T) {
+	var s testSettings
+	err := BindConfig("port=abc;mode=batch;timeout=soon", &s)
+
+	var bindErr *BindError
+	if !errors.As(err, &bindErr) {
+		t.Fatalf("expected bind error but got %v", err)
+	}
+	expected := []string{"host", "port", "timeout", "mode"}
+	if len(bindErr.Errors) != len(expected) {
+		t.Fatalf("expected %d errors but got %v", len(expected), err)
+	}
+	for i, key := range expected {
+		if bindErr.Errors[i].Key != key {
+			t.Errorf("expected error on %s but got %v", key, bindErr.Errors[i])
+		}
+	}
+	if err.Error() != "invalid settings:\n"+
+		"  - host: required key is missing\n"+
+		"  - port: invalid value \"abc\": expected an integer\n"+
+		"  - timeout: invalid value \"soon\": expected a duration, e.g. 30s\n"+
+		"  - mode: invalid value \"batch\": must be one of sync,async" {
+		t.Errorf("unexpected message %s", err.Error())
+	}
+}
@@ -3,35 +3,136 @@ package util
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
 )
 
 // GetConfig parse string value to map[string]string object
-// split config by ";" and value ":"
+// split config by ";" and value by the first "=".
+// A key or value starting with " or ' is quoted, it may hold ";", "=" or surrounding spaces and "\" escapes
+// one of \ ; " ' inside it. Other keys and values are read as is, quotes and backslashes included.
+//
+//	host=http://localhost?a=b;password="p;w=";path=C:\logs;note='it\'s'
 func GetConfig(str string) (map[string]string, error) {
 	if str == "" {
 		return nil, nil
 	}
 	config := make(map[string]string)
-	splittedItemByComma := strings.Split(str, ";")
-	for _, item := range splittedItemByComma {
-		itemTrimmed := strings.Trim(item, " ")
-		if itemTrimmed == "" {
+	for i := 0; i < len(str); {
+		i = skipSpaces(str, i)
+		if i == len(str) {
+			break
+		}
+		if str[i] == ';' {
+			i++
 			continue
 		}
-		setting := strings.Split(itemTrimmed, "=")
-		key := strings.Trim(setting[0], " ")
+		key, next, err := readSetting(str, i, "=;")
+		if err != nil {
+			return nil, err
+		}
 		if key == "" {
 			return nil, errors.New("key must have a value")
 		}
+		i = next
 		var value string
-		if len(setting) > 1 {
-			value = setting[1]
+		if i < len(str) && str[i] == '=' {
+			if value, i, err = readSetting(str, i+1, ";"); err != nil {
+				return nil, err
+			}
 		}
 		if _, found := config[key]; found {
 			return nil, fmt.Errorf("duplicated key %v", key)
 		}
 		config[key] = value
+		if i < len(str) {
+			i++ // skip ";"
+		}
 	}
 	return config, nil
 }
+
+// FormatConfig formats config as a setting string read back identically by GetConfig, keys are sorted
+func FormatConfig(config map[string]string) string {
+	keys := make([]string, 0, len(config))
+	for k := range config {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	items := make([]string, 0, len(keys))
+	for _, k := range keys {
+		items = append(items, quote(k, true)+"="+quote(config[k], false))
+	}
+	return strings.Join(items, ";")
+}
+
+func isEscapable(c byte) bool {
+	return c == '\\' || c == ';' || c == '"' || c == '\''
+}
+
+func isQuote(c byte) bool {
+	return c == '"' || c == '\''
+}
+
+func skipSpaces(s string, i int) int {
+	for i < len(s) && s[i] == ' ' {
+		i++
+	}
+	return i
+}
+
+// readSetting reads the key or value starting at i, ending at one of the terminators or at the end of s,
+// and returns the index of its terminator. A quoted setting is unquoted and may only be followed by spaces.
+func readSetting(s string, i int, terminators string) (string, int, error) {
+	if i < len(s) && isQuote(s[i]) {
+		return readQuoted(s, i, terminators)
+	}
+	end := i
+	for end < len(s) && !strings.ContainsRune(terminators, rune(s[end])) {
+		end++
+	}
+	// settings were trimmed before quoting was supported
+	return strings.TrimRight(s[i:end], " "), end, nil
+}
+
+// readQuoted unquotes the setting starting with the quote at i
+func readQuoted(s string, i int, terminators string) (string, int, error) {
+	var (
+		b     strings.Builder
+		quote = s[i]
+	)
+	for i++; i < len(s); i++ {
+		c := s[i]
+		switch {
+		case c == '\\' && i+1 < len(s) && isEscapable(s[i+1]):
+			i++
+			b.WriteByte(s[i])
+		case c == quote:
+			end := skipSpaces(s, i+1)
+			if end < len(s) && !strings.ContainsRune(terminators, rune(s[end])) {
+				return "", 0, fmt.Errorf("unexpected %q after the quoted setting %q", s[end], s[:i+1])
+			}
+			return b.String(), end, nil
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return "", 0, fmt.Errorf("unterminated quote in setting %q", s)
+}
+
+// quote double quotes s when it would not be read back as is, keys are also quoted when holding "="
+func quote(s string, isKey bool) string {
+	if s == "" || (!strings.Contains(s, ";") && !isQuote(s[0]) && strings.Trim(s, " ") == s && !(isKey && strings.Contains(s, "="))) {
+		return s
+	}
+	var b strings.Builder
+	b.WriteByte('"')
+	for i := 0; i < len(s); i++ {
+		if s[i] == '\\' || s[i] == '"' {
+			b.WriteByte('\\')
+		}
+		b.WriteByte(s[i])
+	}
+	b.WriteByte('"')
+	return b.String()
+}
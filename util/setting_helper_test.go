@@ -1,6 +1,9 @@
 package util
 
-import "testing"
+import (
+	"reflect"
+	"testing"
+)
 
 func TestGetConfig(t *testing.T) {
 	tt := []struct {
@@ -23,6 +26,34 @@ func TestGetConfig(t *testing.T) {
 				"host": "",
 			},
 		},
+		{
+			inputStr: `host=http://localhost/?a=b&c=d; password=cGFzcw==;path=C:\logs`,
+			expectedKeyValue: map[string]string{
+				"host":     "http://localhost/?a=b&c=d",
+				"password": "cGFzcw==",
+				"path":     `C:\logs`,
+			},
+		},
+		{
+			inputStr: `password="p;w=\"d";note='it\'s';name=it\'s;prefix=" x " ;"a=b"=c`,
+			expectedKeyValue: map[string]string{
+				"password": `p;w="d`,
+				"note":     "it's",
+				"name":     `it\'s`,
+				"prefix":   " x ",
+				"a=b":      "c",
+			},
+		},
+		{
+			// only settings starting with a quote are unquoted
+			inputStr: `password=it's;quoted=a"b"c;share=\\server\share;spaced= "x"`,
+			expectedKeyValue: map[string]string{
+				"password": "it's",
+				"quoted":   `a"b"c`,
+				"share":    `\\server\share`,
+				"spaced":   ` "x"`,
+			},
+		},
 	}
 	for i, tc := range tt {
 		c, err := GetConfig(tc.inputStr)
@@ -37,3 +68,33 @@ func TestGetConfig(t *testing.T) {
 	}
 
 }
+
+func TestGetConfigInvalid(t *testing.T) {
+	for _, str := range []string{"=value", "a=1;a=2", `a="unterminated`, `a="b"c`} {
+		if _, err := GetConfig(str); err == nil {
+			t.Errorf("expected error parsing %s", str)
+		}
+	}
+}
+
+func TestFormatConfig(t *testing.T) {
+	config := map[string]string{
+		"host":     "http://localhost/?a=b",
+		"password": `p;w="d\`,
+		"prefix":   " x ",
+		"empty":    "",
+		"a=b":      "c",
+	}
+	str := FormatConfig(config)
+	expected := `"a=b"=c;empty=;host=http://localhost/?a=b;password="p;w=\"d\\";prefix=" x "`
+	if str != expected {
+		t.Errorf("expected %s but got %s", expected, str)
+	}
+	c, err := GetConfig(str)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(c, config) {
+		t.Errorf("expected %v but got %v", config, c)
+	}
+}
@@ -0,0 +1,87 @@
+package util
+
+import (
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// SettingSpec describes a key of a setting string, see SchemaOf
+type SettingSpec struct {
+	Key         string
+	Type        string
+	Default     string
+	Required    bool
+	Enum        []string
+	Unit        string
+	Description string
+}
+
+// SchemaOf returns the settings bound by Bind into the struct target, in field order
+func SchemaOf(target interface{}) []SettingSpec {
+	t := reflect.TypeOf(target)
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	var specs []SettingSpec
+	for i := 0; i < t.NumField(); i++ {
+		field := t.Field(i)
+		if field.Anonymous && field.Type.Kind() == reflect.Struct {
+			specs = append(specs, SchemaOf(reflect.New(field.Type).Interface())...)
+			continue
+		}
+		key := field.Tag.Get(SettingTag)
+		if key == "" || key == "-" || !field.IsExported() {
+			continue
+		}
+		spec := SettingSpec{
+			Key:         key,
+			Type:        typeName(field.Type),
+			Default:     field.Tag.Get(DefaultTag),
+			Required:    field.Tag.Get(RequiredTag) == "true",
+			Unit:        field.Tag.Get(UnitTag),
+			Description: field.Tag.Get(DescriptionTag),
+		}
+		if enum := field.Tag.Get(EnumTag); enum != "" {
+			spec.Enum = splitList(enum)
+		}
+		specs = append(specs, spec)
+	}
+	return specs
+}
+
+// UnknownKeys returns the sorted keys of config not declared by the schema.
+// A spec key ending with ".*" declares all the keys of that prefix, e.g. "level.*".
+func UnknownKeys(config map[string]string, schema []SettingSpec) []string {
+	var unknown []string
+	for k := range config {
+		if !declared(k, schema) {
+			unknown = append(unknown, k)
+		}
+	}
+	sort.Strings(unknown)
+	return unknown
+}
+
+func declared(key string, schema []SettingSpec) bool {
+	for _, spec := range schema {
+		if spec.Key == key {
+			return true
+		}
+		if prefix := strings.TrimSuffix(spec.Key, "*"); prefix != spec.Key && strings.HasPrefix(key, prefix) {
+			return true
+		}
+	}
+	return false
+}
+
+func typeName(t reflect.Type) string {
+	switch {
+	case t == durationType:
+		return "duration"
+	case t.Kind() == reflect.Slice:
+		return "list of " + typeName(t.Elem())
+	default:
+		return t.Kind().String()
+	}
+}
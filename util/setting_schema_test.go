@@ -0,0 +1,42 @@
+package util
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestSchemaOf(t *testing.T) {
+	schema := SchemaOf(&testSettings{})
+	if len(schema) != 6 {
+		t.Fatalf("expected 6 settings but got %v", schema)
+	}
+	expected := []SettingSpec{
+		{Key: "host", Type: "string", Required: true},
+		{Key: "timeout", Type: "duration", Default: "30", Unit: "s"},
+		{Key: "mode", Type: "string", Default: "sync", Enum: []string{"sync", "async"}},
+		{Key: "hosts", Type: "list of string"},
+	}
+	for _, spec := range expected {
+		found := false
+		for _, s := range schema {
+			if s.Key == spec.Key {
+				found = true
+				if !reflect.DeepEqual(s, spec) {
+					t.Errorf("expected %+v but got %+v", spec, s)
+				}
+			}
+		}
+		if !found {
+			t.Errorf("missing setting %s", spec.Key)
+		}
+	}
+}
+
+func TestUnknownKeys(t *testing.T) {
+	schema := append(SchemaOf(testSettings{}), SettingSpec{Key: "level.*"})
+	config := map[string]string{"host": "h", "prot": "80", "level.cqs": "debug", "levels": "info", "Ignored": "x"}
+	unknown := UnknownKeys(config, schema)
+	if !reflect.DeepEqual(unknown, []string{"Ignored", "levels", "prot"}) {
+		t.Errorf("unexpected unknown keys %v", unknown)
+	}
+}